@@ -1,6 +1,9 @@
 package ksql
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type BuilderInterface[T RowInterface] interface {
 	Sharding(sharding Sharding) BuilderInterface[T]
@@ -48,6 +51,8 @@ type BuilderInterface[T RowInterface] interface {
 	For() ForInterface
 	All(ctx context.Context) error
 	First(ctx context.Context) error
+	FirstOrErr(ctx context.Context) error
+	Chunk(ctx context.Context, size int, call func(models []T) error) error
 	Max(ctx context.Context, column string) error
 	Min(ctx context.Context, column string) error
 	Exist(ctx context.Context) (bool, error)
@@ -58,6 +63,34 @@ type BuilderInterface[T RowInterface] interface {
 	Distinct() BuilderInterface[T]
 	FuncDistinct(fun, column, as string) BuilderInterface[T]
 	WithConn(conn ConnectionInterface) BuilderInterface[T]
+	Union(query QueryInterface) BuilderInterface[T]
+	UnionAll(query QueryInterface) BuilderInterface[T]
+	Intersect(query QueryInterface) BuilderInterface[T]
+	Except(query QueryInterface) BuilderInterface[T]
+	With(name string, query QueryInterface, columns ...string) BuilderInterface[T]
+	WithRecursive(name string, query QueryInterface, columns ...string) BuilderInterface[T]
+	WhereExists(sub QueryInterface) BuilderInterface[T]
+	WhereNotExists(sub QueryInterface) BuilderInterface[T]
+	WhereBy(column string, op Op, sub QueryInterface) BuilderInterface[T]
+	WhereColumn(column string, op Op, other string) BuilderInterface[T]
+	WhereRow(columns []string, op Op, values []any) BuilderInterface[T]
+	WhereRowIn(columns []string, rows [][]any) BuilderInterface[T]
+	WhereRowInBy(columns []string, sub QueryInterface) BuilderInterface[T]
+	HavingBy(column string, op Op, sub QueryInterface) BuilderInterface[T]
+	OrderExpress(expresses ...ExpressInterface) BuilderInterface[T]
+	OrderDescExpress(expresses ...ExpressInterface) BuilderInterface[T]
+	WhereMatch(columns []string, query string, mode MatchMode) BuilderInterface[T]
+	ColumnMatch(columns []string, query string, mode MatchMode, as string) BuilderInterface[T]
+	Timeout(d time.Duration) BuilderInterface[T]
+	UseIndex(indexes ...string) BuilderInterface[T]
+	ForceIndex(indexes ...string) BuilderInterface[T]
+	IgnoreIndex(indexes ...string) BuilderInterface[T]
+	Hint(hints ...string) BuilderInterface[T]
+	HintIndex(table string, indexes ...string) BuilderInterface[T]
+	HintNoIndex(table string, indexes ...string) BuilderInterface[T]
+	HintJoinOrder(tables ...string) BuilderInterface[T]
+	HintSetVar(name string, value any) BuilderInterface[T]
+	Cache(ttl time.Duration, key string) BuilderInterface[T]
 }
 
 type TableInterface interface {
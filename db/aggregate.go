@@ -0,0 +1,242 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+
+	ksql "github.com/kovey/db-go/v3"
+	ks "github.com/kovey/db-go/v3/sql"
+)
+
+var (
+	Err_Aggregate_Target   = errors.New("aggregate target must be a struct or map[string]any")
+	Err_Un_Support_Builder = errors.New("builder is not a *db.Builder")
+)
+
+// queryBuilder exposes the query and connection of a builder to the generic aggregate functions
+type queryBuilder interface {
+	_query() ksql.QueryInterface
+	_conn(ctx context.Context) ksql.ConnectionInterface
+}
+
+func (b *Builder[T]) _query() ksql.QueryInterface {
+	return b.query
+}
+
+func _queryOf[T ksql.RowInterface](builder ksql.BuilderInterface[T]) (queryBuilder, error) {
+	b, ok := builder.(queryBuilder)
+	if !ok {
+		return nil, Err_Un_Support_Builder
+	}
+
+	return b, nil
+}
+
+// Agg is one selected expression of Aggregate
+type Agg struct {
+	fun      string
+	column   string
+	as       string
+	distinct bool
+}
+
+// AggColumn selects a plain column, usually a GROUP BY column
+func AggColumn(column, as string) *Agg {
+	return &Agg{column: column, as: as}
+}
+
+// AggCount selects COUNT(*)
+func AggCount(as string) *Agg {
+	return &Agg{fun: "COUNT", column: "*", as: as}
+}
+
+func AggCountDistinct(column, as string) *Agg {
+	return &Agg{fun: "COUNT", column: column, as: as, distinct: true}
+}
+
+func AggSum(column, as string) *Agg {
+	return &Agg{fun: "SUM", column: column, as: as}
+}
+
+func AggAvg(column, as string) *Agg {
+	return &Agg{fun: "AVG", column: column, as: as}
+}
+
+func AggMax(column, as string) *Agg {
+	return &Agg{fun: "MAX", column: column, as: as}
+}
+
+func AggMin(column, as string) *Agg {
+	return &Agg{fun: "MIN", column: column, as: as}
+}
+
+func (a *Agg) apply(q ksql.QueryInterface) {
+	switch {
+	case a.fun == "":
+		q.Column(a.column, a.as)
+	case a.column == "*":
+		var builder strings.Builder
+		builder.WriteString(a.fun)
+		builder.WriteString("(*) AS ")
+		ks.Backtick(a.as, &builder)
+		q.ColumnsExpress(Raw(builder.String()))
+	case a.distinct:
+		q.FuncDistinct(a.fun, a.column, a.as)
+	default:
+		q.Func(a.fun, a.column, a.as)
+	}
+}
+
+func _aggregate[V any, T ksql.RowInterface](ctx context.Context, builder ksql.BuilderInterface[T], agg *Agg) (V, error) {
+	var value V
+	b, err := _queryOf(builder)
+	if err != nil {
+		return value, err
+	}
+
+	q := b._query().Clone()
+	agg.apply(q)
+	var null sql.Null[V]
+	if err := b._conn(ctx).Scan(ctx, q, &null); err != nil {
+		return value, err
+	}
+
+	return null.V, nil
+}
+
+// Sum of column typed as V, zero when no row matches
+func Sum[V any, T ksql.RowInterface](ctx context.Context, builder ksql.BuilderInterface[T], column string) (V, error) {
+	return _aggregate[V](ctx, builder, AggSum(column, "aggregate"))
+}
+
+// Avg of column typed as V, zero when no row matches
+func Avg[V any, T ksql.RowInterface](ctx context.Context, builder ksql.BuilderInterface[T], column string) (V, error) {
+	return _aggregate[V](ctx, builder, AggAvg(column, "aggregate"))
+}
+
+// Max of column typed as V, zero when no row matches
+func Max[V any, T ksql.RowInterface](ctx context.Context, builder ksql.BuilderInterface[T], column string) (V, error) {
+	return _aggregate[V](ctx, builder, AggMax(column, "aggregate"))
+}
+
+// Min of column typed as V, zero when no row matches
+func Min[V any, T ksql.RowInterface](ctx context.Context, builder ksql.BuilderInterface[T], column string) (V, error) {
+	return _aggregate[V](ctx, builder, AggMin(column, "aggregate"))
+}
+
+// GroupCount counts the rows of builder per value of column
+func GroupCount[K comparable, T ksql.RowInterface](ctx context.Context, builder ksql.BuilderInterface[T], column string) (map[K]uint64, error) {
+	b, err := _queryOf(builder)
+	if err != nil {
+		return nil, err
+	}
+
+	q := b._query().Clone()
+	AggColumn(column, "").apply(q)
+	AggCount("count").apply(q)
+	q.Group(column)
+	counts := make(map[K]uint64)
+	err = _rows(ctx, b._conn(ctx), q, func() { clear(counts) }, func(rows *sql.Rows) error {
+		var key K
+		var count uint64
+		if err := rows.Scan(&key, &count); err != nil {
+			return err
+		}
+
+		counts[key] = count
+		return nil
+	})
+	return counts, err
+}
+
+// Aggregate selects aggs over the rows of builder, one R per group (one in total without GROUP BY).
+// R is map[string]any keyed by alias, or a struct whose fields match the aliases by db tag or name
+func Aggregate[R any, T ksql.RowInterface](ctx context.Context, builder ksql.BuilderInterface[T], aggs ...*Agg) ([]R, error) {
+	b, err := _queryOf(builder)
+	if err != nil {
+		return nil, err
+	}
+
+	q := b._query().Clone()
+	for _, agg := range aggs {
+		agg.apply(q)
+	}
+
+	var r R
+	if _, ok := any(r).(map[string]any); ok {
+		maps, err := ScanMapBy(ctx, b._conn(ctx), q)
+		if err != nil {
+			return nil, err
+		}
+
+		result := make([]R, len(maps))
+		for index, m := range maps {
+			result[index] = any(m).(R)
+		}
+		return result, nil
+	}
+
+	if reflect.TypeOf(r) == nil || reflect.TypeOf(r).Kind() != reflect.Struct {
+		return nil, Err_Aggregate_Target
+	}
+
+	var result []R
+	var fields []int
+	err = _rows(ctx, b._conn(ctx), q, func() { result, fields = result[:0], nil }, func(rows *sql.Rows) error {
+		if fields == nil {
+			columns, err := rows.Columns()
+			if err != nil {
+				return err
+			}
+			fields = _structFields(reflect.TypeOf(r), columns)
+		}
+
+		var row R
+		value := reflect.ValueOf(&row).Elem()
+		dests := make([]any, len(fields))
+		for index, field := range fields {
+			if field < 0 {
+				dests[index] = new(any)
+				continue
+			}
+			dests[index] = value.Field(field).Addr().Interface()
+		}
+
+		if err := rows.Scan(dests...); err != nil {
+			return err
+		}
+
+		result = append(result, row)
+		return nil
+	})
+	return result, err
+}
+
+// index of the exported field of typ for each column, -1 when there is none
+func _structFields(typ reflect.Type, columns []string) []int {
+	fields := make([]int, len(columns))
+	for index, column := range columns {
+		fields[index] = -1
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			name := field.Tag.Get("db")
+			if name == "" {
+				name = field.Name
+			}
+
+			if strings.EqualFold(name, column) {
+				fields[index] = i
+				break
+			}
+		}
+	}
+
+	return fields
+}
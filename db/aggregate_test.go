@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	ksql "github.com/kovey/db-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregateValues(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := Open(testDb, "mysql")
+	database = conn
+
+	mock.ExpectPrepare("SELECT SUM(`amount`) AS `aggregate` FROM `order` WHERE `status` = ?").ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"aggregate"}).AddRow(12.5))
+	mock.ExpectPrepare("SELECT AVG(`amount`) AS `aggregate` FROM `order` WHERE `status` = ?").ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"aggregate"}).AddRow(nil))
+	mock.ExpectPrepare("SELECT MAX(`create_time`) AS `aggregate` FROM `order` WHERE `status` = ?").ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"aggregate"}).AddRow("2026-10-01"))
+	mock.ExpectPrepare("SELECT MIN(`id`) AS `aggregate` FROM `order` WHERE `status` = ?").ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"aggregate"}).AddRow(3))
+
+	var rows []*propagation_user
+	builder := func() ksql.BuilderInterface[*propagation_user] {
+		return Rows(&rows).Table("order").Where("status", ksql.Eq, 1)
+	}
+	sum, err := Sum[float64](context.Background(), builder(), "amount")
+	assert.Nil(t, err)
+	assert.Equal(t, 12.5, sum)
+	avg, err := Avg[float64](context.Background(), builder(), "amount")
+	assert.Nil(t, err)
+	assert.Equal(t, float64(0), avg)
+	last, err := Max[string](context.Background(), builder(), "create_time")
+	assert.Nil(t, err)
+	assert.Equal(t, "2026-10-01", last)
+	first, err := Min[int64](context.Background(), builder(), "id")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), first)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestGroupCount(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := Open(testDb, "mysql")
+	database = conn
+
+	mock.ExpectPrepare("SELECT `status`, COUNT(*) AS `count` FROM `order` WHERE `amount` > ? GROUP BY `status`").ExpectQuery().WithArgs(0).WillReturnRows(sqlmock.NewRows([]string{"status", "count"}).AddRow(0, 5).AddRow(1, 7))
+
+	var rows []*propagation_user
+	counts, err := GroupCount[int](context.Background(), Rows(&rows).Table("order").Where("amount", ksql.Gt, 0), "status")
+	assert.Nil(t, err)
+	assert.Equal(t, map[int]uint64{0: 5, 1: 7}, counts)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+type order_stat struct {
+	Day    string `db:"day"`
+	Orders uint64 `db:"orders"`
+	Amount float64
+}
+
+func TestAggregate(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := Open(testDb, "mysql")
+	database = conn
+
+	query := "SELECT `day`, COUNT(*) AS `orders`, SUM(`amount`) AS `amount`, COUNT(DISTINCT `user_id`) AS `users` FROM `order` GROUP BY `day`"
+	for i := 0; i < 2; i++ {
+		mock.ExpectPrepare(query).ExpectQuery().WillReturnRows(sqlmock.NewRows([]string{"day", "orders", "amount", "users"}).AddRow("2026-10-01", 3, 30.5, 2).AddRow("2026-10-02", 1, 9.9, 1))
+	}
+
+	aggs := func() []*Agg {
+		return []*Agg{AggColumn("day", ""), AggCount("orders"), AggSum("amount", "amount"), AggCountDistinct("user_id", "users")}
+	}
+	var rows []*propagation_user
+	stats, err := Aggregate[order_stat](context.Background(), Rows(&rows).Table("order").Group("day"), aggs()...)
+	assert.Nil(t, err)
+	assert.Equal(t, []order_stat{{Day: "2026-10-01", Orders: 3, Amount: 30.5}, {Day: "2026-10-02", Orders: 1, Amount: 9.9}}, stats)
+
+	maps, err := Aggregate[map[string]any](context.Background(), Rows(&rows).Table("order").Group("day"), aggs()...)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(maps))
+	assert.Equal(t, "2026-10-01", maps[0]["day"])
+
+	_, err = Aggregate[int](context.Background(), Rows(&rows).Table("order"), aggs()...)
+	assert.Equal(t, Err_Aggregate_Target, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
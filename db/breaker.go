@@ -0,0 +1,318 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	ksql "github.com/kovey/db-go/v3"
+)
+
+var Err_Circuit_Open = errors.New("circuit open")
+var Err_Bulkhead_Full = errors.New("bulkhead full")
+
+type BreakerState byte
+
+const (
+	Breaker_State_Closed    BreakerState = 0
+	Breaker_State_Open      BreakerState = 1
+	Breaker_State_Half_Open BreakerState = 2
+)
+
+func (b BreakerState) String() string {
+	switch b {
+	case Breaker_State_Open:
+		return "OPEN"
+	case Breaker_State_Half_Open:
+		return "HALF_OPEN"
+	default:
+		return "CLOSED"
+	}
+}
+
+type BreakerConfig struct {
+	// failure rate is counted in windows of this length
+	Window      time.Duration
+	MinRequests int
+	FailureRate float64
+	// time the circuit stays open before probing
+	OpenTimeout time.Duration
+	// probes allowed in half open, all must succeed to close the circuit
+	HalfOpenProbes int
+	// errors counted as failures, nil counts all errors except the ones reported by the server
+	IsFailure func(err error) bool
+	// called once the state changed, outside the lock of the breaker
+	OnStateChange func(from, to BreakerState)
+}
+
+type BulkheadConfig struct {
+	MaxConcurrent int
+	// max time waiting for a free slot, 0 waits until the context is done
+	QueueTimeout time.Duration
+}
+
+func isFailure(err error) bool {
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var myErr *mysql.MySQLError
+	return !errors.As(err, &myErr)
+}
+
+type breaker struct {
+	conf      BreakerConfig
+	state     BreakerState
+	requests  int
+	failures  int
+	windowAt  time.Time
+	openedAt  time.Time
+	probes    int
+	successes int
+	locker    sync.Mutex
+}
+
+func newBreaker(conf BreakerConfig) *breaker {
+	if conf.Window <= 0 {
+		conf.Window = 10 * time.Second
+	}
+	if conf.MinRequests < 1 {
+		conf.MinRequests = 20
+	}
+	if conf.FailureRate <= 0 {
+		conf.FailureRate = 0.5
+	}
+	if conf.OpenTimeout <= 0 {
+		conf.OpenTimeout = 5 * time.Second
+	}
+	if conf.HalfOpenProbes < 1 {
+		conf.HalfOpenProbes = 1
+	}
+	if conf.IsFailure == nil {
+		conf.IsFailure = isFailure
+	}
+
+	return &breaker{conf: conf, windowAt: time.Now()}
+}
+
+type stateChange struct {
+	from BreakerState
+	to   BreakerState
+}
+
+// to switches the state under the lock, the change is reported by notify once the lock is released
+func (b *breaker) to(state BreakerState, now time.Time) *stateChange {
+	from := b.state
+	b.state = state
+	b.requests, b.failures, b.probes, b.successes = 0, 0, 0, 0
+	b.windowAt = now
+	if state == Breaker_State_Open {
+		b.openedAt = now
+	}
+
+	if from == state {
+		return nil
+	}
+
+	return &stateChange{from: from, to: state}
+}
+
+func (b *breaker) notify(change *stateChange) {
+	if change != nil && b.conf.OnStateChange != nil {
+		b.conf.OnStateChange(change.from, change.to)
+	}
+}
+
+func (b *breaker) allow() (bool, error) {
+	b.locker.Lock()
+	probe, change, err := b._allow(time.Now())
+	b.locker.Unlock()
+	b.notify(change)
+	return probe, err
+}
+
+func (b *breaker) _allow(now time.Time) (bool, *stateChange, error) {
+	var change *stateChange
+	switch b.state {
+	case Breaker_State_Open:
+		if now.Sub(b.openedAt) < b.conf.OpenTimeout {
+			return false, nil, Err_Circuit_Open
+		}
+		change = b.to(Breaker_State_Half_Open, now)
+		fallthrough
+	case Breaker_State_Half_Open:
+		if b.probes >= b.conf.HalfOpenProbes {
+			return false, change, Err_Circuit_Open
+		}
+		b.probes++
+		return true, change, nil
+	default:
+		if now.Sub(b.windowAt) >= b.conf.Window {
+			b.requests, b.failures, b.windowAt = 0, 0, now
+		}
+		return false, nil, nil
+	}
+}
+
+// release gives back a probe that never reached the database, its result is not counted
+func (b *breaker) release() {
+	b.locker.Lock()
+	defer b.locker.Unlock()
+	if b.state == Breaker_State_Half_Open && b.probes > 0 {
+		b.probes--
+	}
+}
+
+func (b *breaker) done(probe bool, err error) {
+	b.locker.Lock()
+	change := b._done(probe, err, time.Now())
+	b.locker.Unlock()
+	b.notify(change)
+}
+
+func (b *breaker) _done(probe bool, err error, now time.Time) *stateChange {
+	failed := err != nil && b.conf.IsFailure(err)
+	if probe {
+		if b.state != Breaker_State_Half_Open {
+			return nil
+		}
+
+		if failed {
+			return b.to(Breaker_State_Open, now)
+		}
+
+		b.successes++
+		if b.successes >= b.conf.HalfOpenProbes {
+			return b.to(Breaker_State_Closed, now)
+		}
+		return nil
+	}
+
+	if b.state != Breaker_State_Closed {
+		return nil
+	}
+
+	b.requests++
+	if failed {
+		b.failures++
+	}
+
+	if b.requests >= b.conf.MinRequests && float64(b.failures)/float64(b.requests) >= b.conf.FailureRate {
+		return b.to(Breaker_State_Open, now)
+	}
+	return nil
+}
+
+func (b *breaker) State() BreakerState {
+	b.locker.Lock()
+	defer b.locker.Unlock()
+	return b.state
+}
+
+type guard struct {
+	breaker  *breaker
+	slots    chan struct{}
+	queueOut time.Duration
+}
+
+func newGuard(breaker *BreakerConfig, bulkhead *BulkheadConfig) *guard {
+	if breaker == nil && (bulkhead == nil || bulkhead.MaxConcurrent < 1) {
+		return nil
+	}
+
+	g := &guard{}
+	if breaker != nil {
+		g.breaker = newBreaker(*breaker)
+	}
+	if bulkhead != nil && bulkhead.MaxConcurrent > 0 {
+		g.slots = make(chan struct{}, bulkhead.MaxConcurrent)
+		g.queueOut = bulkhead.QueueTimeout
+	}
+
+	return g
+}
+
+func (g *guard) acquire(ctx context.Context) error {
+	select {
+	case g.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	var timeout <-chan time.Time
+	if g.queueOut > 0 {
+		timer := time.NewTimer(g.queueOut)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case g.slots <- struct{}{}:
+		return nil
+	case <-timeout:
+		return Err_Bulkhead_Full
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (g *guard) do(ctx context.Context, call func() error) error {
+	if g == nil {
+		return call()
+	}
+
+	var probe bool
+	if g.breaker != nil {
+		var err error
+		if probe, err = g.breaker.allow(); err != nil {
+			return err
+		}
+	}
+
+	if g.slots != nil {
+		if err := g.acquire(ctx); err != nil {
+			if probe {
+				g.breaker.release()
+			}
+			return err
+		}
+		defer func() { <-g.slots }()
+	}
+
+	err := call()
+	if g.breaker != nil {
+		g.breaker.done(probe, err)
+	}
+
+	return err
+}
+
+type guardConnection interface {
+	_guard() *guard
+}
+
+func _guarded(ctx context.Context, conn ksql.ConnectionInterface, call func() error) error {
+	if c, ok := conn.(guardConnection); ok {
+		return c._guard().do(ctx, call)
+	}
+
+	return call()
+}
+
+// state of the circuit breaker of conn
+func BreakerStateOf(conn ksql.ConnectionInterface) BreakerState {
+	c, ok := conn.(guardConnection)
+	if !ok {
+		return Breaker_State_Closed
+	}
+
+	g := c._guard()
+	if g == nil || g.breaker == nil {
+		return Breaker_State_Closed
+	}
+
+	return g.breaker.State()
+}
@@ -0,0 +1,132 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	ksql "github.com/kovey/db-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreakerOpenAndHalfOpen(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	var changes []BreakerState
+	conn, err := OpenBy(testDb, Config{DriverName: "mysql", Breaker: &BreakerConfig{
+		MinRequests: 2, FailureRate: 0.5, OpenTimeout: 20 * time.Millisecond,
+		OnStateChange: func(from, to BreakerState) { changes = append(changes, to) },
+	}})
+	assert.Nil(t, err)
+
+	connErr := errors.New("connection refused")
+	mock.ExpectPrepare("SELECT `id` FROM `user`").ExpectQuery().WillReturnError(connErr)
+	mock.ExpectPrepare("SELECT `id` FROM `user`").ExpectQuery().WillReturnError(connErr)
+	var id int
+	assert.NotNil(t, conn.ScanRaw(context.Background(), Raw("SELECT `id` FROM `user`"), &id))
+	assert.NotNil(t, conn.ScanRaw(context.Background(), Raw("SELECT `id` FROM `user`"), &id))
+	assert.Equal(t, Breaker_State_Open, BreakerStateOf(conn))
+
+	err = conn.ScanRaw(context.Background(), Raw("SELECT `id` FROM `user`"), &id)
+	assert.Equal(t, Err_Circuit_Open, err)
+	_, err = conn.Exec(context.Background(), NewUpdate().Table("user").Set("age", 1).Where(NewWhere().Where("id", ksql.Eq, 1)))
+	assert.Equal(t, Err_Circuit_Open, err)
+	assert.Equal(t, Breaker_State_Open, BreakerStateOf(conn.Clone()))
+
+	time.Sleep(30 * time.Millisecond)
+	mock.ExpectPrepare("SELECT `id` FROM `user`").ExpectQuery().WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	assert.Nil(t, conn.ScanRaw(context.Background(), Raw("SELECT `id` FROM `user`"), &id))
+	assert.Equal(t, 1, id)
+	assert.Equal(t, Breaker_State_Closed, BreakerStateOf(conn))
+	assert.Equal(t, []BreakerState{Breaker_State_Open, Breaker_State_Half_Open, Breaker_State_Closed}, changes)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestBreakerIgnoreServerError(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := OpenBy(testDb, Config{DriverName: "mysql", Breaker: &BreakerConfig{MinRequests: 1}})
+
+	mock.ExpectPrepare("INSERT INTO `user` (`name`) VALUES (?)").ExpectExec().WithArgs("kovey").WillReturnError(&mysql.MySQLError{Number: Mysql_Err_Dup_Entry})
+	_, err := conn.Exec(context.Background(), NewInsert().Table("user").Add("name", "kovey"))
+	assert.True(t, errors.Is(err, Err_Duplicate_Key))
+	assert.Equal(t, Breaker_State_Closed, BreakerStateOf(conn))
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestBreakerHalfOpenFailed(t *testing.T) {
+	b := newBreaker(BreakerConfig{MinRequests: 1, OpenTimeout: time.Millisecond, HalfOpenProbes: 2})
+	b.done(false, errors.New("timeout"))
+	assert.Equal(t, Breaker_State_Open, b.State())
+
+	time.Sleep(2 * time.Millisecond)
+	probe, err := b.allow()
+	assert.True(t, probe)
+	assert.Nil(t, err)
+	probe, err = b.allow()
+	assert.True(t, probe)
+	assert.Nil(t, err)
+	_, err = b.allow()
+	assert.Equal(t, Err_Circuit_Open, err)
+
+	b.done(true, nil)
+	assert.Equal(t, Breaker_State_Half_Open, b.State())
+	b.done(true, errors.New("timeout"))
+	assert.Equal(t, Breaker_State_Open, b.State())
+}
+
+func TestBulkheadFull(t *testing.T) {
+	g := newGuard(nil, &BulkheadConfig{MaxConcurrent: 1, QueueTimeout: 10 * time.Millisecond})
+	started := make(chan struct{})
+	release := make(chan struct{})
+	go g.do(context.Background(), func() error {
+		close(started)
+		<-release
+		return nil
+	})
+
+	<-started
+	err := g.do(context.Background(), func() error { return nil })
+	assert.Equal(t, Err_Bulkhead_Full, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	g.queueOut = 0
+	assert.Equal(t, context.Canceled, g.do(ctx, func() error { return nil }))
+
+	close(release)
+	g.queueOut = time.Second
+	assert.Nil(t, g.do(context.Background(), func() error { return nil }))
+	assert.Nil(t, newGuard(nil, &BulkheadConfig{}))
+}
+
+func TestBreakerProbeBulkheadFull(t *testing.T) {
+	var g *guard
+	var states []BreakerState
+	g = newGuard(&BreakerConfig{MinRequests: 1, OpenTimeout: time.Millisecond, OnStateChange: func(from, to BreakerState) {
+		// reading the state in the callback does not deadlock
+		states = append(states, g.breaker.State())
+	}}, &BulkheadConfig{MaxConcurrent: 1, QueueTimeout: time.Millisecond})
+	g.breaker.done(false, errors.New("timeout"))
+	assert.Equal(t, Breaker_State_Open, g.breaker.State())
+
+	time.Sleep(2 * time.Millisecond)
+	g.slots <- struct{}{}
+	called := false
+	err := g.do(context.Background(), func() error {
+		called = true
+		return nil
+	})
+	assert.Equal(t, Err_Bulkhead_Full, err)
+	assert.False(t, called)
+	// the probe was not counted and its slot is free again
+	assert.Equal(t, Breaker_State_Half_Open, g.breaker.State())
+
+	<-g.slots
+	assert.Nil(t, g.do(context.Background(), func() error { return nil }))
+	assert.Equal(t, Breaker_State_Closed, g.breaker.State())
+	assert.Equal(t, []BreakerState{Breaker_State_Open, Breaker_State_Half_Open, Breaker_State_Closed}, states)
+}
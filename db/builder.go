@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"time"
 
 	ksql "github.com/kovey/db-go/v3"
 )
@@ -11,6 +12,7 @@ type Builder[T ksql.RowInterface] struct {
 	conn   ksql.ConnectionInterface
 	model  T
 	models *[]T
+	cache  *cacheOption
 }
 
 func NewBuilder[T ksql.RowInterface](model T) *Builder[T] {
@@ -238,31 +240,59 @@ func (b *Builder[T]) For() ksql.ForInterface {
 }
 
 func (b *Builder[T]) All(ctx context.Context) error {
-	return QueryBy(ctx, b._conn(), b.query, b.models)
+	conn := b._conn(ctx)
+	if store := b._cacheStore(conn); store != nil {
+		return b._allCached(ctx, conn, store)
+	}
+
+	return QueryBy(ctx, conn, b.query, b.models)
 }
 
 func (b *Builder[T]) First(ctx context.Context) error {
+	if b.cache != nil {
+		conn := b._conn(ctx)
+		if store := b._cacheStore(conn); store != nil {
+			return b._firstCached(ctx, conn, store)
+		}
+	}
+
 	if b.conn == nil {
 		return QueryRow(ctx, b.query, b.model)
 	}
 
-	return b.conn.QueryRow(ctx, b.query, b.model)
+	return ConnOf(ctx, b.conn).QueryRow(ctx, b.query, b.model)
+}
+
+// FirstOrErr is First returning Err_Not_Found when no row matches
+func (b *Builder[T]) FirstOrErr(ctx context.Context) error {
+	return b.First(Strict(ctx))
 }
 
-func (b *Builder[T]) _conn() ksql.ConnectionInterface {
+// the transaction of ctx wins over the connection of the builder
+func (b *Builder[T]) _conn(ctx context.Context) ksql.ConnectionInterface {
 	if b.conn != nil {
-		return b.conn
+		return ConnOf(ctx, b.conn)
 	}
 
-	return database
+	return _database(ctx)
 }
 
 func _scanNum[T uint64 | float64](ctx context.Context, conn ksql.ConnectionInterface, query ksql.SqlInterface) (T, error) {
+	var num T
+	err := _retry(ctx, conn, func() error {
+		var err error
+		num, err = _scanNumOnce[T](ctx, conn, query)
+		return err
+	})
+	return num, err
+}
+
+func _scanNumOnce[T uint64 | float64](ctx context.Context, conn ksql.ConnectionInterface, query ksql.SqlInterface) (T, error) {
 	cc := NewContext(ctx)
 	cc.SqlLogStart(query)
 	defer cc.SqlLogEnd()
 
-	stmt, err := conn.Prepare(ctx, query)
+	stmt, err := _prepare(ctx, conn, query)
 	if err != nil {
 		return 0, err
 	}
@@ -288,13 +318,13 @@ func _scanNum[T uint64 | float64](ctx context.Context, conn ksql.ConnectionInter
 func (b *Builder[T]) SumFloat(ctx context.Context, column string) (float64, error) {
 	q := b.query.Clone()
 	q.Func("SUM", column, column)
-	return _scanNum[float64](ctx, b._conn(), q)
+	return _scanNum[float64](ctx, b._conn(ctx), q)
 }
 
 func (b *Builder[T]) SumInt(ctx context.Context, column string) (uint64, error) {
 	q := b.query.Clone()
 	q.Func("SUM", column, column)
-	return _scanNum[uint64](ctx, b._conn(), q)
+	return _scanNum[uint64](ctx, b._conn(ctx), q)
 }
 
 func (b *Builder[T]) Count(ctx context.Context) (uint64, error) {
@@ -302,16 +332,31 @@ func (b *Builder[T]) Count(ctx context.Context) (uint64, error) {
 	// avoiding only_full_group_by errors in MySQL.
 	q := b.query.Clone()
 	q.ColumnsExpress(Raw("COUNT(1) as count"))
-	return _scanNum[uint64](ctx, b._conn(), q)
+	conn := b._conn(ctx)
+	if store := b._cacheStore(conn); store != nil {
+		return b._countCached(ctx, conn, store, q)
+	}
+
+	return _scanNum[uint64](ctx, conn, q)
 }
 
 func (b *Builder[T]) Exist(ctx context.Context) (bool, error) {
 	b.query.Limit(1)
+	var ok bool
+	err := _retry(ctx, b._conn(ctx), func() error {
+		var err error
+		ok, err = b._exist(ctx)
+		return err
+	})
+	return ok, err
+}
+
+func (b *Builder[T]) _exist(ctx context.Context) (bool, error) {
 	cc := NewContext(ctx)
 	cc.SqlLogStart(b.query)
 	defer cc.SqlLogEnd()
 
-	stmt, err := b._conn().Prepare(ctx, b.query)
+	stmt, err := _prepare(ctx, b._conn(ctx), b.query)
 	if err != nil {
 		return false, err
 	}
@@ -332,12 +377,13 @@ func offset(page, pageSize int64) int {
 
 func (b *Builder[T]) Pagination(ctx context.Context, page, pageSize int64) (ksql.PaginationInterface[T], error) {
 	ctx = NewContext(ctx)
+	// cloned before the page limit, a compound query keeps its limit inside the clone
+	total := &Builder[T]{query: b.query.Clone(), conn: b.conn, cache: b.cache}
 	b.query.Limit(int(pageSize)).Offset(offset(page, pageSize))
 	if err := b.All(ctx); err != nil {
 		return nil, err
 	}
 
-	total := &Builder[T]{query: b.query.Clone(), conn: b.conn}
 	count, err := total.Limit(1).Offset(0).Count(ctx)
 	if err != nil {
 		return nil, err
@@ -356,6 +402,141 @@ func (b *Builder[T]) WithConn(conn ksql.ConnectionInterface) ksql.BuilderInterfa
 	return b
 }
 
+func (b *Builder[T]) Union(query ksql.QueryInterface) ksql.BuilderInterface[T] {
+	b.query.Union(query)
+	return b
+}
+
+func (b *Builder[T]) UnionAll(query ksql.QueryInterface) ksql.BuilderInterface[T] {
+	b.query.UnionAll(query)
+	return b
+}
+
+func (b *Builder[T]) Intersect(query ksql.QueryInterface) ksql.BuilderInterface[T] {
+	b.query.Intersect(query)
+	return b
+}
+
+func (b *Builder[T]) Except(query ksql.QueryInterface) ksql.BuilderInterface[T] {
+	b.query.Except(query)
+	return b
+}
+
+func (b *Builder[T]) With(name string, query ksql.QueryInterface, columns ...string) ksql.BuilderInterface[T] {
+	b.query.With(name, query, columns...)
+	return b
+}
+
+func (b *Builder[T]) WithRecursive(name string, query ksql.QueryInterface, columns ...string) ksql.BuilderInterface[T] {
+	b.query.WithRecursive(name, query, columns...)
+	return b
+}
+
+func (b *Builder[T]) WhereExists(sub ksql.QueryInterface) ksql.BuilderInterface[T] {
+	b.query.WhereExists(sub)
+	return b
+}
+
+func (b *Builder[T]) WhereNotExists(sub ksql.QueryInterface) ksql.BuilderInterface[T] {
+	b.query.WhereNotExists(sub)
+	return b
+}
+
+func (b *Builder[T]) WhereBy(column string, op ksql.Op, sub ksql.QueryInterface) ksql.BuilderInterface[T] {
+	b.query.WhereBy(column, op, sub)
+	return b
+}
+
+func (b *Builder[T]) WhereColumn(column string, op ksql.Op, other string) ksql.BuilderInterface[T] {
+	b.query.WhereColumn(column, op, other)
+	return b
+}
+
+func (b *Builder[T]) WhereRow(columns []string, op ksql.Op, values []any) ksql.BuilderInterface[T] {
+	b.query.WhereRow(columns, op, values)
+	return b
+}
+
+func (b *Builder[T]) WhereRowIn(columns []string, rows [][]any) ksql.BuilderInterface[T] {
+	b.query.WhereRowIn(columns, rows)
+	return b
+}
+
+func (b *Builder[T]) WhereRowInBy(columns []string, sub ksql.QueryInterface) ksql.BuilderInterface[T] {
+	b.query.WhereRowInBy(columns, sub)
+	return b
+}
+
+func (b *Builder[T]) HavingBy(column string, op ksql.Op, sub ksql.QueryInterface) ksql.BuilderInterface[T] {
+	b.query.HavingBy(column, op, sub)
+	return b
+}
+
+func (b *Builder[T]) OrderExpress(expresses ...ksql.ExpressInterface) ksql.BuilderInterface[T] {
+	b.query.OrderExpress(expresses...)
+	return b
+}
+
+func (b *Builder[T]) OrderDescExpress(expresses ...ksql.ExpressInterface) ksql.BuilderInterface[T] {
+	b.query.OrderDescExpress(expresses...)
+	return b
+}
+
+func (b *Builder[T]) WhereMatch(columns []string, query string, mode ksql.MatchMode) ksql.BuilderInterface[T] {
+	b.query.WhereMatch(columns, query, mode)
+	return b
+}
+
+func (b *Builder[T]) ColumnMatch(columns []string, query string, mode ksql.MatchMode, as string) ksql.BuilderInterface[T] {
+	b.query.ColumnMatch(columns, query, mode, as)
+	return b
+}
+
+func (b *Builder[T]) Timeout(d time.Duration) ksql.BuilderInterface[T] {
+	b.query.Timeout(d)
+	return b
+}
+
+func (b *Builder[T]) UseIndex(indexes ...string) ksql.BuilderInterface[T] {
+	b.query.UseIndex(indexes...)
+	return b
+}
+
+func (b *Builder[T]) ForceIndex(indexes ...string) ksql.BuilderInterface[T] {
+	b.query.ForceIndex(indexes...)
+	return b
+}
+
+func (b *Builder[T]) IgnoreIndex(indexes ...string) ksql.BuilderInterface[T] {
+	b.query.IgnoreIndex(indexes...)
+	return b
+}
+
+func (b *Builder[T]) Hint(hints ...string) ksql.BuilderInterface[T] {
+	b.query.Hint(hints...)
+	return b
+}
+
+func (b *Builder[T]) HintIndex(table string, indexes ...string) ksql.BuilderInterface[T] {
+	b.query.HintIndex(table, indexes...)
+	return b
+}
+
+func (b *Builder[T]) HintNoIndex(table string, indexes ...string) ksql.BuilderInterface[T] {
+	b.query.HintNoIndex(table, indexes...)
+	return b
+}
+
+func (b *Builder[T]) HintJoinOrder(tables ...string) ksql.BuilderInterface[T] {
+	b.query.HintJoinOrder(tables...)
+	return b
+}
+
+func (b *Builder[T]) HintSetVar(name string, value any) ksql.BuilderInterface[T] {
+	b.query.HintSetVar(name, value)
+	return b
+}
+
 func (b *Builder[T]) Max(ctx context.Context, column string) error {
 	b.query.Func("MAX", column, column)
 	return b.First(ctx)
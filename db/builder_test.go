@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	ksql "github.com/kovey/db-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilderUnion(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := Open(testDb, "mysql")
+
+	mock.ExpectPrepare("(SELECT `id`, `age`, `name`, `create_time`, `balance` FROM `user` WHERE `age` > ?) UNION ALL (SELECT `id`, `age`, `name`, `create_time`, `balance` FROM `user_archive` WHERE `age` > ?) ORDER BY `id` DESC").
+		ExpectQuery().WithArgs(18, 20).WillReturnRows(sqlmock.NewRows([]string{"id", "age", "name", "create_time", "balance"}).AddRow(2, 21, "archived", "2025-01-01 00:00:00", 0).AddRow(1, 19, "kovey", "2025-01-01 00:00:00", 1.5))
+	var users []*test_user
+	archive := NewQuery().Table("user_archive").Columns(newTestUser().Columns()...).Where("age", ksql.Gt, 20)
+	builder := Rows(&users).WithConn(conn).Table("user").Columns(newTestUser().Columns()...).Where("age", ksql.Gt, 18).UnionAll(archive).OrderDesc("id")
+	assert.Nil(t, builder.All(context.Background()))
+	assert.Equal(t, 2, len(users))
+	assert.Equal(t, "archived", users[0].Name)
+
+	mock.ExpectPrepare("SELECT COUNT(1) as count FROM ((SELECT `id`, `age`, `name`, `create_time`, `balance` FROM `user` WHERE `age` > ?) UNION ALL (SELECT `id`, `age`, `name`, `create_time`, `balance` FROM `user_archive` WHERE `age` > ?) ORDER BY `id` DESC) AS `compound`").
+		ExpectQuery().WithArgs(18, 20).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	count, err := builder.Count(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), count)
+	assert.Nil(t, mock.ExpectationsWereMet())
+
+	// the page limit of a compound query does not limit its total
+	mock.ExpectPrepare("(SELECT `id`, `age`, `name`, `create_time`, `balance` FROM `user` WHERE `age` > ?) UNION ALL (SELECT `id`, `age`, `name`, `create_time`, `balance` FROM `user_archive` WHERE `age` > ?) ORDER BY `id` DESC LIMIT ? OFFSET ?").
+		ExpectQuery().WithArgs(18, 20, 1, 0).WillReturnRows(sqlmock.NewRows([]string{"id", "age", "name", "create_time", "balance"}).AddRow(2, 21, "archived", "2025-01-01 00:00:00", 0))
+	mock.ExpectPrepare("SELECT COUNT(1) as count FROM ((SELECT `id`, `age`, `name`, `create_time`, `balance` FROM `user` WHERE `age` > ?) UNION ALL (SELECT `id`, `age`, `name`, `create_time`, `balance` FROM `user_archive` WHERE `age` > ?) ORDER BY `id` DESC) AS `compound` LIMIT ? OFFSET ?").
+		ExpectQuery().WithArgs(18, 20, 1, 0).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	var paged []*test_user
+	page, err := Rows(&paged).WithConn(conn).Table("user").Columns(newTestUser().Columns()...).Where("age", ksql.Gt, 18).UnionAll(archive).OrderDesc("id").Pagination(context.Background(), 1, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), page.TotalCount())
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestBuilderWhereExists(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := Open(testDb, "mysql")
+
+	mock.ExpectPrepare("SELECT `u`.`id` FROM `user` AS `u` WHERE EXISTS (SELECT `o`.`id` FROM `orders` AS `o` WHERE `o`.`user_id` = `u`.`id` AND `o`.`amount` > ?) AND (`u`.`age`, `u`.`id`) > (?, ?) LIMIT ?").
+		ExpectQuery().WithArgs(100, 18, 5, 1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	orders := NewQuery().Table("orders").As("o").Columns("o.id").WhereColumn("o.user_id", ksql.Eq, "u.id").Where("o.amount", ksql.Gt, 100)
+	ok, err := Rows(&[]*test_user{}).WithConn(conn).Table("user").As("u").Columns("u.id").WhereExists(orders).WhereRow([]string{"u.age", "u.id"}, ksql.Gt, []any{18, 5}).Exist(context.Background())
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
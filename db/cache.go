@@ -0,0 +1,407 @@
+package db
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	ksql "github.com/kovey/db-go/v3"
+)
+
+const Cache_Default_Size = 1024
+
+// CacheStore keeps query results of Builder.Cache, tags are the table names a result was read from.
+// Values are a uint64 count, the column values of one row ([]any) or of many rows ([][]any)
+type CacheStore interface {
+	Get(ctx context.Context, key string) (any, bool)
+	// ttl <= 0 keeps the value until it is evicted or invalidated
+	Set(ctx context.Context, key string, value any, ttl time.Duration, tags ...string)
+	// Invalidate drops every value stored with one of tags
+	Invalidate(ctx context.Context, tags ...string)
+}
+
+var cacheStore CacheStore = NewLruStore(Cache_Default_Size)
+
+// set global cache store, nil disables Builder.Cache
+func SetCacheStore(store CacheStore) {
+	cacheStore = store
+}
+
+type lruEntry struct {
+	key      string
+	value    any
+	expireAt time.Time
+	tags     []string
+}
+
+// LruStore is the in memory CacheStore, the least recently read value is evicted when it is full
+type LruStore struct {
+	mu    sync.Mutex
+	size  int
+	items map[string]*list.Element
+	lru   *list.List
+	tags  map[string]map[string]struct{}
+}
+
+func NewLruStore(size int) *LruStore {
+	if size <= 0 {
+		size = Cache_Default_Size
+	}
+
+	return &LruStore{size: size, items: make(map[string]*list.Element), lru: list.New(), tags: make(map[string]map[string]struct{})}
+}
+
+func (l *LruStore) Get(ctx context.Context, key string) (any, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	el, ok := l.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := el.Value.(*lruEntry)
+	if !entry.expireAt.IsZero() && time.Now().After(entry.expireAt) {
+		l.remove(el)
+		return nil, false
+	}
+
+	l.lru.MoveToFront(el)
+	return entry.value, true
+}
+
+func (l *LruStore) Set(ctx context.Context, key string, value any, ttl time.Duration, tags ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if el, ok := l.items[key]; ok {
+		l.remove(el)
+	}
+
+	entry := &lruEntry{key: key, value: value, tags: tags}
+	if ttl > 0 {
+		entry.expireAt = time.Now().Add(ttl)
+	}
+
+	l.items[key] = l.lru.PushFront(entry)
+	for _, tag := range tags {
+		keys, ok := l.tags[tag]
+		if !ok {
+			keys = make(map[string]struct{})
+			l.tags[tag] = keys
+		}
+		keys[key] = struct{}{}
+	}
+
+	for l.lru.Len() > l.size {
+		l.remove(l.lru.Back())
+	}
+}
+
+func (l *LruStore) Invalidate(ctx context.Context, tags ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, tag := range tags {
+		for key := range l.tags[tag] {
+			if el, ok := l.items[key]; ok {
+				l.remove(el)
+			}
+		}
+		delete(l.tags, tag)
+	}
+}
+
+// Len is the count of stored values, expired ones included until they are read or evicted
+func (l *LruStore) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.lru.Len()
+}
+
+func (l *LruStore) remove(el *list.Element) {
+	entry := l.lru.Remove(el).(*lruEntry)
+	delete(l.items, entry.key)
+	for _, tag := range entry.tags {
+		if keys, ok := l.tags[tag]; ok {
+			delete(keys, entry.key)
+			if len(keys) == 0 {
+				delete(l.tags, tag)
+			}
+		}
+	}
+}
+
+// InvalidateCacheBy drops the cached results of tables, when conn is in a transaction they are dropped again on commit
+// so reads of other sessions cached meanwhile do not outlive the transaction
+func InvalidateCacheBy(ctx context.Context, conn ksql.ConnectionInterface, tables ...string) {
+	store := cacheStore
+	if store == nil || len(tables) == 0 {
+		return
+	}
+
+	store.Invalidate(ctx, tables...)
+	if conn == nil || !conn.InTransaction() {
+		return
+	}
+
+	if h, ok := conn.(txHookConnection); ok {
+		h.OnCommit(func() { store.Invalidate(ctx, tables...) })
+	}
+}
+
+func InvalidateCache(ctx context.Context, tables ...string) {
+	InvalidateCacheBy(ctx, _database(ctx), tables...)
+}
+
+type cacheOption struct {
+	ttl time.Duration
+	key string
+}
+
+// Cache keeps the result of First, All and Count in the cache store for ttl, tagged with the tables the query reads.
+// key names the result on the connection, empty key uses the sql and binds; reads in a transaction, reads matching
+// no row and queries joining raw expresses skip the cache
+func (b *Builder[T]) Cache(ttl time.Duration, key string) ksql.BuilderInterface[T] {
+	b.cache = &cacheOption{ttl: ttl, key: key}
+	return b
+}
+
+// the cache store when b caches and conn is not in a transaction
+func (b *Builder[T]) _cacheStore(conn ksql.ConnectionInterface) CacheStore {
+	if b.cache == nil || cacheStore == nil || conn == nil || conn.InTransaction() {
+		return nil
+	}
+
+	return cacheStore
+}
+
+// the key of query on conn and the tables it reads, ok is false when the query reads a source that can not be tagged
+func (b *Builder[T]) _cacheKey(kind string, conn ksql.ConnectionInterface, query ksql.QueryInterface) (string, []string, bool) {
+	tables, ok := query.Tables()
+	if !ok {
+		return "", nil, false
+	}
+
+	// results of different databases or shard nodes never share a key
+	if b.cache.key != "" {
+		return fmt.Sprintf("%s:%p:%s", kind, conn.Database(), b.cache.key), tables, true
+	}
+
+	// binds are collected while the sql is built
+	sql := query.Prepare()
+	return fmt.Sprintf("%s:%p:%s %v", kind, conn.Database(), sql, query.Binds()), tables, true
+}
+
+// the column values of row, copied out of its scan destinations
+func _cacheValues(row ksql.RowInterface) []any {
+	dests := row.Values()
+	values := make([]any, len(dests))
+	for index, dest := range dests {
+		values[index] = _cacheCopy(reflect.Indirect(reflect.ValueOf(dest))).Interface()
+	}
+
+	return values
+}
+
+// cacheScanner replays cached column values into the scan destinations of a row
+type cacheScanner struct {
+	values []any
+}
+
+func (c *cacheScanner) Scan(dests ...any) error {
+	if len(dests) != len(c.values) {
+		return fmt.Errorf("cache: expected %d destination arguments in Scan, not %d", len(c.values), len(dests))
+	}
+
+	for index, dest := range dests {
+		target := reflect.ValueOf(dest).Elem()
+		if c.values[index] == nil {
+			target.SetZero()
+			continue
+		}
+
+		value := _cacheCopy(reflect.ValueOf(c.values[index]))
+		if !value.Type().AssignableTo(target.Type()) {
+			return fmt.Errorf("cache: can not assign %s to %s", value.Type(), target.Type())
+		}
+		target.Set(value)
+	}
+
+	return nil
+}
+
+// pointers, slices, maps and the exported fields of structs are copied deeply so rows read from the cache
+// do not share them with the cache, fields of scanners such as JSON[T] included
+func _cacheCopy(value reflect.Value) reflect.Value {
+	switch value.Kind() {
+	case reflect.Pointer:
+		if value.IsNil() {
+			return value
+		}
+
+		copied := reflect.New(value.Type().Elem())
+		copied.Elem().Set(_cacheCopy(value.Elem()))
+		return copied
+	case reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(_cacheCopy(value.Elem()))
+		return copied
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+
+		copied := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			reflect.Copy(copied, value)
+			return copied
+		}
+
+		for i := 0; i < value.Len(); i++ {
+			copied.Index(i).Set(_cacheCopy(value.Index(i)))
+		}
+		return copied
+	case reflect.Array:
+		copied := reflect.New(value.Type()).Elem()
+		for i := 0; i < value.Len(); i++ {
+			copied.Index(i).Set(_cacheCopy(value.Index(i)))
+		}
+		return copied
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+
+		copied := reflect.MakeMapWithSize(value.Type(), value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			copied.SetMapIndex(iter.Key(), _cacheCopy(iter.Value()))
+		}
+		return copied
+	case reflect.Struct:
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(value)
+		// unexported fields, such as those of time.Time, are kept as they are
+		for i := 0; i < copied.NumField(); i++ {
+			if field := copied.Field(i); field.CanSet() {
+				field.Set(_cacheCopy(value.Field(i)))
+			}
+		}
+		return copied
+	}
+
+	return value
+}
+
+func (b *Builder[T]) _allCached(ctx context.Context, conn ksql.ConnectionInterface, store CacheStore) error {
+	key, tags, ok := b._cacheKey("all", conn, b.query)
+	if !ok {
+		return QueryBy(ctx, conn, b.query, b.models)
+	}
+
+	if value, ok := store.Get(ctx, key); ok {
+		if rows, ok := value.([][]any); ok {
+			var m T
+			models := make([]T, 0, len(rows))
+			for _, row := range rows {
+				tmp := m.Clone()
+				if err := tmp.Scan(&cacheScanner{values: row}, tmp); err != nil {
+					return err
+				}
+
+				tmp.Sharding(b.query.GetSharding())
+				model, ok := tmp.(T)
+				if !ok {
+					continue
+				}
+
+				model.WithConn(conn)
+				models = append(models, model)
+			}
+
+			*b.models = append(*b.models, models...)
+			return nil
+		}
+	}
+
+	count := len(*b.models)
+	if err := QueryBy(ctx, conn, b.query, b.models); err != nil {
+		return err
+	}
+
+	rows := make([][]any, 0, len(*b.models)-count)
+	for _, model := range (*b.models)[count:] {
+		rows = append(rows, _cacheValues(model))
+	}
+	store.Set(ctx, key, rows, b.cache.ttl, tags...)
+	return nil
+}
+
+func (b *Builder[T]) _firstCached(ctx context.Context, conn ksql.ConnectionInterface, store CacheStore) error {
+	key, tags, ok := b._cacheKey("first", conn, b.query)
+	if !ok {
+		return QueryRowBy(ctx, conn, b.query, b.model)
+	}
+
+	if value, ok := store.Get(ctx, key); ok {
+		if row, ok := value.([]any); ok {
+			if err := b.model.Scan(&cacheScanner{values: row}, b.model); err != nil {
+				return err
+			}
+
+			b.model.WithConn(conn)
+			b.model.Sharding(b.query.GetSharding())
+			return nil
+		}
+	}
+
+	// a read matching no row leaves the model empty and is not cached
+	if err := QueryRowBy(Strict(ctx), conn, b.query, b.model); err != nil {
+		if errors.Is(err, Err_Not_Found) && !IsStrict(ctx) {
+			return nil
+		}
+
+		return err
+	}
+
+	store.Set(ctx, key, _cacheValues(b.model), b.cache.ttl, tags...)
+	return nil
+}
+
+func (b *Builder[T]) _countCached(ctx context.Context, conn ksql.ConnectionInterface, store CacheStore, query ksql.QueryInterface) (uint64, error) {
+	key, tags, ok := b._cacheKey("count", conn, query)
+	if !ok {
+		return _scanNum[uint64](ctx, conn, query)
+	}
+
+	if value, ok := store.Get(ctx, key); ok {
+		if count, ok := value.(uint64); ok {
+			return count, nil
+		}
+	}
+
+	count, err := _scanNum[uint64](ctx, conn, query)
+	if err != nil {
+		return 0, err
+	}
+
+	store.Set(ctx, key, count, b.cache.ttl, tags...)
+	return count, nil
+}
+
+// _invalidate passes the result of a write on table through, dropping the cached results of table when it succeeded
+func _invalidate(ctx context.Context, conn ksql.ConnectionInterface, table string) func(int64, error) (int64, error) {
+	return func(affected int64, err error) (int64, error) {
+		if err == nil {
+			InvalidateCacheBy(ctx, conn, table)
+		}
+
+		return affected, err
+	}
+}
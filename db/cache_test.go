@@ -0,0 +1,207 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	ksql "github.com/kovey/db-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+type spy_store struct {
+	*LruStore
+	invalidated [][]string
+}
+
+func (s *spy_store) Invalidate(ctx context.Context, tags ...string) {
+	s.invalidated = append(s.invalidated, tags)
+	s.LruStore.Invalidate(ctx, tags...)
+}
+
+func useCacheStore(t *testing.T, store CacheStore) {
+	old := cacheStore
+	SetCacheStore(store)
+	t.Cleanup(func() { SetCacheStore(old) })
+}
+
+func TestLruStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewLruStore(2)
+	store.Set(ctx, "a", 1, 0, "user")
+	store.Set(ctx, "b", 2, 0, "user", "orders")
+	value, ok := store.Get(ctx, "a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	// b is the least recently read
+	store.Set(ctx, "c", 3, 0, "orders")
+	_, ok = store.Get(ctx, "b")
+	assert.False(t, ok)
+	assert.Equal(t, 2, store.Len())
+
+	store.Invalidate(ctx, "orders")
+	_, ok = store.Get(ctx, "c")
+	assert.False(t, ok)
+	_, ok = store.Get(ctx, "a")
+	assert.True(t, ok)
+
+	store.Set(ctx, "d", 4, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	_, ok = store.Get(ctx, "d")
+	assert.False(t, ok)
+	assert.Equal(t, 1, store.Len())
+}
+
+func TestCacheScannerCopy(t *testing.T) {
+	source := NewJSON(map[string][]int{"ids": {1, 2}})
+	scanner := &cacheScanner{values: []any{_cacheCopy(reflect.ValueOf(source)).Interface()}}
+	// the row the values were read from does not change the cache
+	source.Data["ids"][0] = 9
+
+	var first JSON[map[string][]int]
+	assert.Nil(t, scanner.Scan(&first))
+	assert.Equal(t, []int{1, 2}, first.Data["ids"])
+
+	// a row read from the cache does not change it either
+	first.Data["ids"][1] = 9
+	first.Data["names"] = nil
+	var second JSON[map[string][]int]
+	assert.Nil(t, scanner.Scan(&second))
+	assert.Equal(t, map[string][]int{"ids": {1, 2}}, second.Data)
+}
+
+func TestBuilderCache(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := Open(testDb, "mysql")
+	useCacheStore(t, NewLruStore(16))
+	ctx := context.Background()
+	columns := newTestUser().Columns()
+
+	mock.ExpectPrepare("SELECT `id`, `age`, `name`, `create_time`, `balance` FROM `user` WHERE `age` > ?").
+		ExpectQuery().WithArgs(18).WillReturnRows(sqlmock.NewRows(columns).AddRow(1, 19, "kovey", "2025-01-01 00:00:00", 1.5))
+	for i := 0; i < 2; i++ {
+		var users []*test_user
+		assert.Nil(t, Rows(&users).WithConn(conn).Table("user").Columns(columns...).Where("age", ksql.Gt, 18).Cache(time.Minute, "").All(ctx))
+		assert.Equal(t, 1, len(users))
+		assert.Equal(t, "kovey", users[0].Name)
+		assert.Equal(t, 1.5, users[0].Balance)
+	}
+
+	mock.ExpectPrepare("SELECT `id`, `age`, `name`, `create_time`, `balance` FROM `user` WHERE `id` = ?").
+		ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows(columns).AddRow(1, 19, "kovey", "2025-01-01 00:00:00", 1.5))
+	for i := 0; i < 2; i++ {
+		user := newTestUser()
+		assert.Nil(t, Build(user).WithConn(conn).Table("user").Columns(columns...).Where("id", ksql.Eq, 1).Cache(time.Minute, "user:1").First(ctx))
+		assert.Equal(t, int64(1), user.Id)
+		assert.Equal(t, 19, user.Age)
+	}
+
+	mock.ExpectPrepare("SELECT COUNT(1) as count FROM `user`").ExpectQuery().WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	for i := 0; i < 2; i++ {
+		count, err := Rows(&[]*test_user{}).WithConn(conn).Table("user").Cache(time.Minute, "").Count(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(3), count)
+	}
+
+	// a write on user drops every cached result tagged with it
+	mock.ExpectPrepare("UPDATE `user` SET `age` = ? WHERE `id` = ?").ExpectExec().WithArgs(20, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	_, err := UpdateBy(ctx, conn, "user", NewData().Set("age", 20), NewWhere().Where("id", ksql.Eq, 1))
+	assert.Nil(t, err)
+
+	mock.ExpectPrepare("SELECT `id`, `age`, `name`, `create_time`, `balance` FROM `user` WHERE `id` = ?").
+		ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows(columns).AddRow(1, 20, "kovey", "2025-01-01 00:00:00", 1.5))
+	user := newTestUser()
+	assert.Nil(t, Build(user).WithConn(conn).Table("user").Columns(columns...).Where("id", ksql.Eq, 1).Cache(time.Minute, "user:1").First(ctx))
+	assert.Equal(t, 20, user.Age)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestBuilderCacheTransaction(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := Open(testDb, "mysql")
+	store := &spy_store{LruStore: NewLruStore(16)}
+	useCacheStore(t, store)
+	ctx := context.Background()
+
+	mock.ExpectBegin()
+	mock.ExpectPrepare("SELECT COUNT(1) as count FROM `user`").ExpectQuery().WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectPrepare("DELETE FROM `user` WHERE `id` = ?").ExpectExec().WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	err := conn.Transaction(ctx, func(ctx context.Context, tx ksql.ConnectionInterface) error {
+		// reads in a transaction skip the cache
+		count, err := Rows(&[]*test_user{}).WithConn(tx).Table("user").Cache(time.Minute, "users").Count(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(3), count)
+
+		_, err = DeleteBy(ctx, tx, "user", NewWhere().Where("id", ksql.Eq, 1))
+		assert.Equal(t, [][]string{{"user"}}, store.invalidated)
+		return err
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, store.Len())
+	assert.Equal(t, [][]string{{"user"}, {"user"}}, store.invalidated)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestBuilderCacheSources(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := Open(testDb, "mysql")
+	otherDb, otherMock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer otherDb.Close()
+	other, _ := Open(otherDb, "mysql")
+	useCacheStore(t, NewLruStore(16))
+	ctx := context.Background()
+	columns := newTestUser().Columns()
+
+	// a read matching no row is not cached
+	for i := 0; i < 2; i++ {
+		mock.ExpectPrepare("SELECT `id`, `age`, `name`, `create_time`, `balance` FROM `user` WHERE `id` = ?").ExpectQuery().WithArgs(2).WillReturnRows(sqlmock.NewRows(columns))
+		user := newTestUser()
+		assert.Nil(t, Build(user).WithConn(conn).Table("user").Columns(columns...).Where("id", ksql.Eq, 2).Cache(time.Minute, "user:2").First(ctx))
+		assert.Equal(t, int64(0), user.Id)
+	}
+	assert.Nil(t, mock.ExpectationsWereMet())
+
+	// the table of a subquery tags the result
+	count := func() uint64 {
+		orders := NewQuery().Table("orders").Columns("user_id")
+		count, err := Rows(&[]*test_user{}).WithConn(conn).Table("user").WhereInBy("id", orders).Cache(time.Minute, "").Count(ctx)
+		assert.Nil(t, err)
+		return count
+	}
+	mock.ExpectPrepare("SELECT COUNT(1) as count FROM `user` WHERE `id` IN (SELECT `user_id` FROM `orders`)").ExpectQuery().WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	assert.Equal(t, uint64(3), count())
+	assert.Equal(t, uint64(3), count())
+	InvalidateCacheBy(ctx, conn, "orders")
+	mock.ExpectPrepare("SELECT COUNT(1) as count FROM `user` WHERE `id` IN (SELECT `user_id` FROM `orders`)").ExpectQuery().WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+	assert.Equal(t, uint64(4), count())
+	assert.Nil(t, mock.ExpectationsWereMet())
+
+	// a join by raw express can not be tagged and is not cached
+	for i := 0; i < 2; i++ {
+		mock.ExpectPrepare("SELECT COUNT(1) as count FROM `user` INNER JOIN orders ON orders.user_id = user.id").ExpectQuery().WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		builder := Rows(&[]*test_user{}).WithConn(conn).Table("user").Cache(time.Minute, "joined")
+		builder.JoinExpress(Raw("INNER JOIN orders ON orders.user_id = user.id"))
+		_, err := builder.Count(ctx)
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, mock.ExpectationsWereMet())
+
+	// the same key on another connection is another result
+	mock.ExpectPrepare("SELECT COUNT(1) as count FROM `user`").ExpectQuery().WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	otherMock.ExpectPrepare("SELECT COUNT(1) as count FROM `user`").ExpectQuery().WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(7))
+	for _, c := range []ksql.ConnectionInterface{conn, other, conn, other} {
+		_, err := Rows(&[]*test_user{}).WithConn(c).Table("user").Cache(time.Minute, "users").Count(ctx)
+		assert.Nil(t, err)
+	}
+	total, _ := Rows(&[]*test_user{}).WithConn(other).Table("user").Cache(time.Minute, "users").Count(ctx)
+	assert.Equal(t, uint64(7), total)
+	assert.Nil(t, mock.ExpectationsWereMet())
+	assert.Nil(t, otherMock.ExpectationsWereMet())
+}
@@ -0,0 +1,207 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+
+	ksql "github.com/kovey/db-go/v3"
+)
+
+var Err_Chunk_No_Primary = errors.New("chunk needs a model with primary key in its columns")
+
+const Chunk_Default_Size = 1000
+
+// Chunk calls call with the models of builder size rows at a time, walking the primary key instead of OFFSET,
+// each chunk selects the model columns ordered by primary key, the order, limit and offset of builder are ignored
+func (b *Builder[T]) Chunk(ctx context.Context, size int, call func(models []T) error) error {
+	var m T
+	model, ok := m.Clone().(ksql.ModelInterface)
+	if !ok {
+		return Err_Chunk_No_Primary
+	}
+
+	index := -1
+	columns := model.Columns()
+	for i, column := range columns {
+		if column == model.PrimaryId() {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return Err_Chunk_No_Primary
+	}
+
+	if size <= 0 {
+		size = Chunk_Default_Size
+	}
+
+	var last any
+	for {
+		q := b.query.Keyset(model.PrimaryId(), last, size)
+		q.Columns(columns...)
+
+		var models []T
+		if err := QueryBy(ctx, b._conn(ctx), q, &models); err != nil {
+			return err
+		}
+
+		if len(models) == 0 {
+			return nil
+		}
+
+		if err := call(models); err != nil {
+			return err
+		}
+
+		if len(models) < size {
+			return nil
+		}
+
+		last = reflect.Indirect(reflect.ValueOf(models[len(models)-1].Values()[index])).Interface()
+	}
+}
+
+// Chunk calls call with the models of builder size rows at a time, see Builder.Chunk
+func Chunk[T ksql.ModelInterface](ctx context.Context, builder ksql.BuilderInterface[T], size int, call func(models []T) error) error {
+	return builder.Chunk(ctx, size, call)
+}
+
+// ChunkProgress reports a finished chunk of ChunkUpdate
+type ChunkProgress struct {
+	Min      int64 // min primary key of the table when the update began
+	Max      int64 // max primary key of the table when the update began
+	From     int64 // first primary key of the chunk
+	To       int64 // last primary key of the chunk
+	Affected int64 // rows changed by the chunk
+	Total    int64 // rows changed so far
+}
+
+// Percent of the primary key range done
+func (c ChunkProgress) Percent() float64 {
+	if c.To >= c.Max {
+		return 100
+	}
+
+	return float64(c.To-c.Min+1) * 100 / float64(c.Max-c.Min+1)
+}
+
+type ChunkConfig struct {
+	Sleep             time.Duration         // sleep between chunks
+	MaxThreadsRunning uint64                // pause while Threads_running of the server is above it, 0 disables the check
+	Pause             time.Duration         // wait between Threads_running checks, default 1s
+	Progress          func(p ChunkProgress) // called after every chunk
+}
+
+func (c *ChunkConfig) _throttle(ctx context.Context, conn ksql.ConnectionInterface) error {
+	if c == nil {
+		return nil
+	}
+
+	if c.Sleep > 0 {
+		if err := _sleep(ctx, c.Sleep); err != nil {
+			return err
+		}
+	}
+
+	if c.MaxThreadsRunning == 0 {
+		return nil
+	}
+
+	pause := c.Pause
+	if pause <= 0 {
+		pause = time.Second
+	}
+
+	for {
+		var name string
+		var running uint64
+		if err := conn.ScanRaw(ctx, Raw("SHOW GLOBAL STATUS LIKE 'Threads_running'"), &name, &running); err != nil {
+			return err
+		}
+
+		if running <= c.MaxThreadsRunning {
+			return nil
+		}
+
+		if err := _sleep(ctx, pause); err != nil {
+			return err
+		}
+	}
+}
+
+func _sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// the conditions of where as one parenthesized express, so OR groups stay inside the chunk range
+func _whereExpress(where ksql.WhereInterface) ksql.ExpressInterface {
+	if where == nil || where.Empty() {
+		return nil
+	}
+
+	var builder strings.Builder
+	where.Build(&builder)
+	return Raw("("+strings.TrimPrefix(builder.String(), "WHERE ")+")", where.Binds()...)
+}
+
+// ChunkUpdateBy sets data on the rows of table matching where, size integer primary keys at a time:
+// pk >= from AND pk < from + size, every chunk is its own statement so locks are held briefly
+func ChunkUpdateBy(ctx context.Context, conn ksql.ConnectionInterface, table, pkColumn string, size int64, data *Data, where ksql.WhereInterface, conf *ChunkConfig) (int64, error) {
+	if size <= 0 {
+		size = Chunk_Default_Size
+	}
+
+	var lower, upper sql.NullInt64
+	bounds := NewQuery().Table(table).Func("MIN", pkColumn, "min_id").Func("MAX", pkColumn, "max_id")
+	if err := conn.Scan(ctx, bounds, &lower, &upper); err != nil {
+		return 0, err
+	}
+
+	if !lower.Valid {
+		return 0, nil
+	}
+
+	cond := _whereExpress(where)
+	var total int64
+	for from := lower.Int64; from <= upper.Int64; from += size {
+		w := NewWhere().Where(pkColumn, ksql.Ge, from).Where(pkColumn, ksql.Lt, from+size)
+		if cond != nil {
+			w.Express(cond)
+		}
+
+		affected, err := UpdateBy(ctx, conn, table, data, w)
+		if err != nil {
+			return total, err
+		}
+
+		total += affected
+		if conf != nil && conf.Progress != nil {
+			conf.Progress(ChunkProgress{Min: lower.Int64, Max: upper.Int64, From: from, To: min(from+size-1, upper.Int64), Affected: affected, Total: total})
+		}
+
+		if from+size <= upper.Int64 {
+			if err := conf._throttle(ctx, conn); err != nil {
+				return total, err
+			}
+		}
+	}
+
+	return total, nil
+}
+
+// ChunkUpdate runs ChunkUpdateBy on the global connection, conf may be nil
+func ChunkUpdate(ctx context.Context, table, pkColumn string, size int64, data *Data, where ksql.WhereInterface, conf *ChunkConfig) (int64, error) {
+	return ChunkUpdateBy(ctx, _database(ctx), table, pkColumn, size, data, where, conf)
+}
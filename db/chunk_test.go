@@ -0,0 +1,147 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	ksql "github.com/kovey/db-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+type chunk_user struct {
+	propagation_user
+}
+
+func (c *chunk_user) Clone() ksql.RowInterface                           { return &chunk_user{} }
+func (c *chunk_user) Table() string                                      { return "user" }
+func (c *chunk_user) Columns() []string                                  { return []string{"id", "name"} }
+func (c *chunk_user) PrimaryId() string                                  { return "id" }
+func (c *chunk_user) Save(ctx context.Context) error                     { return nil }
+func (c *chunk_user) Delete(ctx context.Context) error                   { return nil }
+func (c *chunk_user) OnUpdateBefore(conn ksql.ConnectionInterface) error { return nil }
+func (c *chunk_user) OnUpdateAfter(conn ksql.ConnectionInterface) error  { return nil }
+func (c *chunk_user) OnCreateBefore(conn ksql.ConnectionInterface) error { return nil }
+func (c *chunk_user) OnCreateAfter(conn ksql.ConnectionInterface) error  { return nil }
+func (c *chunk_user) OnDeleteBefore(conn ksql.ConnectionInterface) error { return nil }
+func (c *chunk_user) OnDeleteAfter(conn ksql.ConnectionInterface) error  { return nil }
+func (c *chunk_user) Empty() bool                                        { return c.Id == 0 }
+
+func TestChunk(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := Open(testDb, "mysql")
+	database = conn
+
+	mock.ExpectPrepare("SELECT `id`, `name` FROM `user` WHERE (`age` > ?) ORDER BY `id` ASC LIMIT ?").ExpectQuery().WithArgs(18, 2).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a").AddRow(3, "b"))
+	mock.ExpectPrepare("SELECT `id`, `name` FROM `user` WHERE `id` > ? AND (`age` > ?) ORDER BY `id` ASC LIMIT ?").ExpectQuery().WithArgs(3, 18, 2).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(4, "c").AddRow(7, "d"))
+	mock.ExpectPrepare("SELECT `id`, `name` FROM `user` WHERE `id` > ? AND (`age` > ?) ORDER BY `id` ASC LIMIT ?").ExpectQuery().WithArgs(7, 18, 2).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(9, "e"))
+
+	var chunks [][]string
+	var users []*chunk_user
+	err := Chunk(context.Background(), Models(&users).Where("age", ksql.Gt, 18), 2, func(models []*chunk_user) error {
+		var names []string
+		for _, model := range models {
+			names = append(names, model.Name)
+		}
+		chunks = append(chunks, names)
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{{"a", "b"}, {"c", "d"}, {"e"}}, chunks)
+	assert.Nil(t, mock.ExpectationsWereMet())
+
+	var rows []*propagation_user
+	assert.Equal(t, Err_Chunk_No_Primary, Rows(&rows).Table("user").Chunk(context.Background(), 2, func(models []*propagation_user) error { return nil }))
+}
+
+func TestChunkOrWhere(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := Open(testDb, "mysql")
+	database = conn
+
+	// the primary key range narrows the OR group too
+	mock.ExpectPrepare("SELECT `id`, `name` FROM `user` WHERE (`age` > ? OR (`vip` = ?)) ORDER BY `id` ASC LIMIT ?").ExpectQuery().WithArgs(18, 1, 2).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a").AddRow(2, "b"))
+	mock.ExpectPrepare("SELECT `id`, `name` FROM `user` WHERE `id` > ? AND (`age` > ? OR (`vip` = ?)) ORDER BY `id` ASC LIMIT ?").ExpectQuery().WithArgs(2, 18, 1, 2).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(5, "c"))
+
+	var ids []int
+	var users []*chunk_user
+	builder := Models(&users).Where("age", ksql.Gt, 18).OrWhere(func(w ksql.WhereInterface) { w.Where("vip", ksql.Eq, 1) })
+	err := builder.Chunk(context.Background(), 2, func(models []*chunk_user) error {
+		for _, model := range models {
+			ids = append(ids, model.Id)
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 2, 5}, ids)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestChunkIgnoresOrder(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := Open(testDb, "mysql")
+	database = conn
+
+	mock.ExpectPrepare("SELECT `id`, `name` FROM `user` WHERE (`age` > ?) ORDER BY `id` ASC LIMIT ?").ExpectQuery().WithArgs(18, 2).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a").AddRow(3, "b"))
+	mock.ExpectPrepare("SELECT `id`, `name` FROM `user` WHERE `id` > ? AND (`age` > ?) ORDER BY `id` ASC LIMIT ?").ExpectQuery().WithArgs(3, 18, 2).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+	var users []*chunk_user
+	builder := Models(&users).Where("age", ksql.Gt, 18).OrderDesc("created").Limit(10).Offset(20)
+	count := 0
+	assert.Nil(t, builder.Chunk(context.Background(), 2, func(models []*chunk_user) error {
+		count += len(models)
+		return nil
+	}))
+	assert.Equal(t, 2, count)
+	assert.Nil(t, mock.ExpectationsWereMet())
+
+	// the builder keeps its own order, limit and offset
+	mock.ExpectPrepare("SELECT `id`, `name` FROM `user` WHERE `age` > ? ORDER BY `created` DESC LIMIT ? OFFSET ?").ExpectQuery().WithArgs(18, 10, 20).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	assert.Nil(t, builder.All(context.Background()))
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestChunkUpdate(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := Open(testDb, "mysql")
+	database = conn
+
+	mock.ExpectPrepare("SELECT MIN(`id`) AS `min_id`, MAX(`id`) AS `max_id` FROM `user`").ExpectQuery().WillReturnRows(sqlmock.NewRows([]string{"min_id", "max_id"}).AddRow(1, 250))
+	for _, from := range []int64{1, 101, 201} {
+		mock.ExpectPrepare("UPDATE `user` SET `status` = ? WHERE `id` >= ? AND `id` < ? AND (`status` = ? OR (`deleted` = ?))").ExpectExec().WithArgs(1, from, from+100, 0, 1).WillReturnResult(sqlmock.NewResult(0, 10))
+		if from < 201 {
+			mock.ExpectPrepare("SHOW GLOBAL STATUS LIKE 'Threads_running'").ExpectQuery().WillReturnRows(sqlmock.NewRows([]string{"Variable_name", "Value"}).AddRow("Threads_running", 3))
+		}
+	}
+
+	var progress []ChunkProgress
+	where := NewWhere().Where("status", ksql.Eq, 0).OrWhere(func(o ksql.WhereInterface) { o.Where("deleted", ksql.Eq, 1) })
+	total, err := ChunkUpdate(context.Background(), "user", "id", 100, NewData().Set("status", 1), where, &ChunkConfig{
+		MaxThreadsRunning: 10, Progress: func(p ChunkProgress) { progress = append(progress, p) },
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(30), total)
+	assert.Equal(t, []ChunkProgress{
+		{Min: 1, Max: 250, From: 1, To: 100, Affected: 10, Total: 10},
+		{Min: 1, Max: 250, From: 101, To: 200, Affected: 10, Total: 20},
+		{Min: 1, Max: 250, From: 201, To: 250, Affected: 10, Total: 30},
+	}, progress)
+	assert.Equal(t, float64(40), progress[0].Percent())
+	assert.Equal(t, float64(100), progress[2].Percent())
+	assert.Nil(t, mock.ExpectationsWereMet())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mock.ExpectPrepare("SELECT MIN(`id`) AS `min_id`, MAX(`id`) AS `max_id` FROM `user`").ExpectQuery().WillReturnRows(sqlmock.NewRows([]string{"min_id", "max_id"}).AddRow(1, 250))
+	mock.ExpectPrepare("UPDATE `user` SET `status` = ? WHERE `id` >= ? AND `id` < ?").ExpectExec().WithArgs(1, 1, 101).WillReturnResult(sqlmock.NewResult(0, 10))
+	total, err = ChunkUpdate(ctx, "user", "id", 100, NewData().Set("status", 1), nil, &ChunkConfig{Sleep: time.Minute, Progress: func(p ChunkProgress) { cancel() }})
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, int64(10), total)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -4,10 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"runtime/debug"
 
 	ksql "github.com/kovey/db-go/v3"
-	"github.com/kovey/db-go/v3/db/driver"
-	ks "github.com/kovey/db-go/v3/sql"
 )
 
 type Connection struct {
@@ -15,6 +14,15 @@ type Connection struct {
 	database   *sql.DB
 	driverName string
 	transCount int
+	guard      *guard
+	retry      *RetryPolicy
+	stmts      *stmtCache
+	// run sql on the db or tx directly instead of preparing it
+	interpolate bool
+	// convert a panic of a transaction call to TxError instead of panicking again
+	recoverPanic bool
+	// hooks of the transaction and each savepoint inside it
+	hooks []*txHooks
 }
 
 func (c *Connection) DriverName() string {
@@ -26,16 +34,19 @@ func (c *Connection) Database() *sql.DB {
 }
 
 func (c *Connection) Clone() ksql.ConnectionInterface {
-	return &Connection{database: c.database, driverName: c.driverName, tx: nil}
+	return &Connection{database: c.database, driverName: c.driverName, tx: nil, guard: c.guard, retry: c.retry, stmts: c.stmts, interpolate: c.interpolate, recoverPanic: c.recoverPanic}
 }
 
-func (c *Connection) BeginTo(ctx context.Context, point string) error {
-	if !driver.SupportSavePoint(c.driverName) {
-		return Err_Un_Support_Save_Point
-	}
+func (c *Connection) _guard() *guard {
+	return c.guard
+}
 
-	_, err := c.ExecRaw(ctx, ks.Raw("SAVEPOINT ?", point))
-	return err
+func (c *Connection) _retryPolicy() *RetryPolicy {
+	return c.retry
+}
+
+func (c *Connection) BeginTo(ctx context.Context, point string) error {
+	return c._savePoint(ctx, "SAVEPOINT", point)
 }
 
 func (c *Connection) beginTo(ctx context.Context) error {
@@ -49,44 +60,36 @@ func (c *Connection) beginTo(ctx context.Context) error {
 		return err
 	}
 
+	c.hooks = append(c.hooks, &txHooks{})
 	return nil
 }
 
 func (c *Connection) RollbackTo(ctx context.Context, point string) error {
-	if !driver.SupportSavePoint(c.driverName) {
-		return Err_Un_Support_Save_Point
-	}
-
-	_, err := c.ExecRaw(ctx, ks.Raw("ROLLBACK TO SAVEPOINT ?", point))
-	return err
+	return c._savePoint(ctx, "ROLLBACK TO SAVEPOINT", point)
 }
 
 func (c *Connection) rollbackTo(ctx context.Context) error {
-	if err := c.RollbackTo(ctx, fmt.Sprintf("trans_%d", c.transCount)); err != nil {
-		c.transCount--
-		return err
-	}
-
+	hooks := c._popHooks()
+	err := c.RollbackTo(ctx, fmt.Sprintf("trans_%d", c.transCount))
 	c.transCount--
-	return nil
+	hooks.rolledBack()
+	return err
 }
 
 func (c *Connection) CommitTo(ctx context.Context, point string) error {
-	if !driver.SupportSavePoint(c.driverName) {
-		return Err_Un_Support_Save_Point
-	}
-
-	_, err := c.ExecRaw(ctx, ks.Raw("RELEASE SAVEPOINT ?", point))
-	return err
+	return c._savePoint(ctx, "RELEASE SAVEPOINT", point)
 }
 
 func (c *Connection) commitTo(ctx context.Context) error {
+	hooks := c._popHooks()
 	if err := c.CommitTo(ctx, fmt.Sprintf("trans_%d", c.transCount)); err != nil {
 		c.transCount--
+		hooks.rolledBack()
 		return err
 	}
 
 	c.transCount--
+	c._topHooks().merge(hooks)
 	return nil
 }
 
@@ -95,13 +98,16 @@ func (c *Connection) Begin(ctx context.Context, options *sql.TxOptions) error {
 		return c.beginTo(ctx)
 	}
 
-	tx, err := c.database.BeginTx(ctx, options)
-	if err != nil {
-		return err
-	}
+	return c.guard.do(ctx, func() error {
+		tx, err := c.database.BeginTx(ctx, options)
+		if err != nil {
+			return err
+		}
 
-	c.tx = tx
-	return nil
+		c.tx = tx
+		c.hooks = []*txHooks{{}}
+		return nil
+	})
 }
 
 func (c *Connection) Rollback(ctx context.Context) error {
@@ -113,8 +119,11 @@ func (c *Connection) Rollback(ctx context.Context) error {
 		return c.rollbackTo(ctx)
 	}
 
-	defer c.reset()
-	return c.tx.Rollback()
+	hooks := c._popHooks()
+	err := c.tx.Rollback()
+	c.reset()
+	hooks.rolledBack()
+	return err
 }
 
 func (c *Connection) Commit(ctx context.Context) error {
@@ -126,24 +135,52 @@ func (c *Connection) Commit(ctx context.Context) error {
 		return c.commitTo(ctx)
 	}
 
-	defer c.reset()
-	return c.tx.Commit()
+	hooks := c._popHooks()
+	err := c.tx.Commit()
+	c.reset()
+	if err != nil {
+		hooks.rolledBack()
+		return err
+	}
+
+	hooks.committed()
+	return nil
 }
 
 func (c *Connection) reset() {
 	c.tx = nil
+	c.hooks = nil
 }
 
 func (c *Connection) Transaction(ctx context.Context, call func(ctx context.Context, conn ksql.ConnectionInterface) error) ksql.TxError {
 	return c.TransactionBy(ctx, nil, call)
 }
 
-func (c *Connection) TransactionBy(ctx context.Context, options *sql.TxOptions, call func(ctx context.Context, conn ksql.ConnectionInterface) error) ksql.TxError {
+// a panic of call rolls back and panics again, or is returned as PanicErr when recoverPanic is set
+func (c *Connection) TransactionBy(ctx context.Context, options *sql.TxOptions, call func(ctx context.Context, conn ksql.ConnectionInterface) error) (txErr ksql.TxError) {
 	if err := c.Begin(ctx, options); err != nil {
 		return &TxErr{beginErr: err}
 	}
 
-	callErr := call(ctx, c)
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+
+		err := &TxErr{callErr: &PanicErr{Value: r, Stack: debug.Stack()}}
+		if rollbackErr := c.Rollback(ctx); rollbackErr != nil {
+			err.rollbackErr = rollbackErr
+		}
+
+		if !c.recoverPanic {
+			panic(r)
+		}
+
+		txErr = err
+	}()
+
+	callErr := call(WithTx(ctx, c), c)
 	if callErr != nil {
 		txErr := &TxErr{callErr: callErr}
 		if err := c.Rollback(ctx); err != nil {
@@ -199,11 +236,21 @@ func _errRaw(err error, op ksql.ExpressInterface) error {
 }
 
 func (c *Connection) Exec(ctx context.Context, op ksql.SqlInterface) (int64, error) {
+	var id int64
+	err := c.guard.do(ctx, func() error {
+		var err error
+		id, err = c._exec(ctx, op)
+		return err
+	})
+	return id, err
+}
+
+func (c *Connection) _exec(ctx context.Context, op ksql.SqlInterface) (int64, error) {
 	cc := NewContext(ctx)
 	cc.SqlLogStart(op)
 	defer cc.SqlLogEnd()
 
-	stmt, err := c.Prepare(cc, op)
+	stmt, err := _prepare(cc, c, op)
 	if err != nil {
 		return 0, err
 	}
@@ -225,11 +272,17 @@ func (c *Connection) Exec(ctx context.Context, op ksql.SqlInterface) (int64, err
 }
 
 func (c *Connection) QueryRow(ctx context.Context, op ksql.QueryInterface, model ksql.RowInterface) error {
+	return _retry(ctx, c, func() error {
+		return c._queryRow(ctx, op, model)
+	})
+}
+
+func (c *Connection) _queryRow(ctx context.Context, op ksql.QueryInterface, model ksql.RowInterface) error {
 	cc := NewContext(ctx)
 	cc.SqlLogStart(op)
 	defer cc.SqlLogEnd()
 
-	stmt, err := c.Prepare(cc, op)
+	stmt, err := _prepare(cc, c, op)
 	if err != nil {
 		return err
 	}
@@ -243,7 +296,7 @@ func (c *Connection) QueryRow(ctx context.Context, op ksql.QueryInterface, model
 	if err := model.Scan(row, model); err != nil {
 		if err == sql.ErrNoRows {
 			model.WithConn(c)
-			return nil
+			return _err(_noRows(ctx), op)
 		}
 
 		return _err(err, op)
@@ -255,15 +308,21 @@ func (c *Connection) QueryRow(ctx context.Context, op ksql.QueryInterface, model
 }
 
 func (c *Connection) QueryRowRaw(ctx context.Context, raw ksql.ExpressInterface, model ksql.RowInterface) error {
-	if raw.IsExec() {
+	if !_isQuery(raw) {
 		return _errRaw(Err_Sql_Not_Query, raw)
 	}
 
+	return _retry(ctx, c, func() error {
+		return c._queryRowRaw(ctx, raw, model)
+	})
+}
+
+func (c *Connection) _queryRowRaw(ctx context.Context, raw ksql.ExpressInterface, model ksql.RowInterface) error {
 	cc := NewContext(ctx)
 	cc.RawSqlLogStart(raw)
 	defer cc.SqlLogEnd()
 
-	stmt, err := c.PrepareRaw(cc, raw)
+	stmt, err := _prepareRaw(cc, c, raw)
 	if err != nil {
 		return err
 	}
@@ -277,7 +336,7 @@ func (c *Connection) QueryRowRaw(ctx context.Context, raw ksql.ExpressInterface,
 	if err := model.Scan(row, model); err != nil {
 		if err == sql.ErrNoRows {
 			model.WithConn(c)
-			return nil
+			return _errRaw(_noRows(ctx), raw)
 		}
 
 		return _errRaw(err, raw)
@@ -302,11 +361,21 @@ func (c *Connection) ExecRaw(ctx context.Context, raw ksql.ExpressInterface) (sq
 		return nil, _errRaw(Err_Sql_Not_Exec, raw)
 	}
 
+	var result sql.Result
+	err := c.guard.do(ctx, func() error {
+		var err error
+		result, err = c._execRaw(ctx, raw)
+		return err
+	})
+	return result, err
+}
+
+func (c *Connection) _execRaw(ctx context.Context, raw ksql.ExpressInterface) (sql.Result, error) {
 	cc := NewContext(ctx)
 	cc.RawSqlLogStart(raw)
 	defer cc.SqlLogEnd()
 
-	stmt, err := c.PrepareRaw(cc, raw)
+	stmt, err := _prepareRaw(cc, c, raw)
 	if err != nil {
 		return nil, err
 	}
@@ -321,15 +390,21 @@ func (c *Connection) InTransaction() bool {
 }
 
 func (c *Connection) ScanRaw(ctx context.Context, raw ksql.ExpressInterface, data ...any) error {
-	if raw.IsExec() {
+	if !_isQuery(raw) {
 		return _errRaw(Err_Sql_Not_Query, raw)
 	}
 
+	return _retry(ctx, c, func() error {
+		return c._scanRaw(ctx, raw, data...)
+	})
+}
+
+func (c *Connection) _scanRaw(ctx context.Context, raw ksql.ExpressInterface, data ...any) error {
 	cc := NewContext(ctx)
 	cc.RawSqlLogStart(raw)
 	defer cc.SqlLogEnd()
 
-	stmt, err := c.PrepareRaw(cc, raw)
+	stmt, err := _prepareRaw(cc, c, raw)
 	if err != nil {
 		return err
 	}
@@ -342,7 +417,7 @@ func (c *Connection) ScanRaw(ctx context.Context, raw ksql.ExpressInterface, dat
 
 	if err := row.Scan(data...); err != nil {
 		if err == sql.ErrNoRows {
-			return nil
+			return _errRaw(_noRows(ctx), raw)
 		}
 
 		return _errRaw(err, raw)
@@ -352,11 +427,17 @@ func (c *Connection) ScanRaw(ctx context.Context, raw ksql.ExpressInterface, dat
 }
 
 func (c *Connection) Scan(ctx context.Context, query ksql.QueryInterface, data ...any) error {
+	return _retry(ctx, c, func() error {
+		return c._scan(ctx, query, data...)
+	})
+}
+
+func (c *Connection) _scan(ctx context.Context, query ksql.QueryInterface, data ...any) error {
 	cc := NewContext(ctx)
 	cc.SqlLogStart(query)
 	defer cc.SqlLogEnd()
 
-	stmt, err := c.Prepare(cc, query)
+	stmt, err := _prepare(cc, c, query)
 	if err != nil {
 		return err
 	}
@@ -364,12 +445,12 @@ func (c *Connection) Scan(ctx context.Context, query ksql.QueryInterface, data .
 
 	row := stmt.QueryRowContext(cc, query.Binds()...)
 	if row.Err() != nil {
-		return _err(err, query)
+		return _err(row.Err(), query)
 	}
 
 	if err := row.Scan(data...); err != nil {
 		if err == sql.ErrNoRows {
-			return nil
+			return _err(_noRows(ctx), query)
 		}
 
 		return _err(err, query)
@@ -1,13 +1,17 @@
 package db
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
 	"errors"
+	"slices"
+	"strings"
 	"time"
 
 	ksql "github.com/kovey/db-go/v3"
 	"github.com/kovey/db-go/v3/logger"
+	ks "github.com/kovey/db-go/v3/sql"
 )
 
 var Err_Un_Support_Operate = errors.New("unsupport operate")
@@ -27,6 +31,12 @@ type Config struct {
 	MaxOpenConns   int
 	LogOpened      bool
 	LogMax         int
+	Retry          *RetryPolicy
+	Breaker        *BreakerConfig
+	Bulkhead       *BulkheadConfig
+	StmtCache      *StmtCacheConfig // cache prepared statements per connection pool
+	Interpolate    bool             // skip preparing, run sql with binds directly, pair with interpolateParams=true of mysql dsn
+	RecoverPanic   bool             // return a panic of a transaction call as PanicErr instead of panicking again
 }
 
 func Database() *sql.DB {
@@ -41,6 +51,18 @@ func Open(conn *sql.DB, driverName string) (ksql.ConnectionInterface, error) {
 	return &Connection{database: conn, driverName: driverName}, nil
 }
 
+// open connection with the retry policy, circuit breaker, bulkhead, statement cache and interpolation of conf
+func OpenBy(conn *sql.DB, conf Config) (ksql.ConnectionInterface, error) {
+	if err := conn.Ping(); err != nil {
+		return nil, err
+	}
+
+	return &Connection{
+		database: conn, driverName: conf.DriverName, guard: newGuard(conf.Breaker, conf.Bulkhead), stmts: newStmtCache(conf.StmtCache), interpolate: conf.Interpolate,
+		recoverPanic: conf.RecoverPanic, retry: conf.Retry,
+	}, nil
+}
+
 func Get() (ksql.ConnectionInterface, error) {
 	if database == nil {
 		return nil, Err_Database_Not_Initialized
@@ -59,6 +81,14 @@ func InitBy(db *sql.DB, driverName string) error {
 	return nil
 }
 
+// apply pool settings of conf to db
+func ApplyPool(db *sql.DB, conf Config) {
+	db.SetConnMaxIdleTime(conf.MaxIdleTime)
+	db.SetConnMaxLifetime(conf.MaxLifeTime)
+	db.SetMaxIdleConns(conf.MaxIdleConns)
+	db.SetMaxOpenConns(conf.MaxOpenConns)
+}
+
 // init global connection
 func Init(conf Config) error {
 	db, err := sql.Open(conf.DriverName, conf.DataSourceName)
@@ -66,16 +96,17 @@ func Init(conf Config) error {
 		return err
 	}
 
-	db.SetConnMaxIdleTime(conf.MaxIdleTime)
-	db.SetConnMaxLifetime(conf.MaxLifeTime)
-	db.SetMaxIdleConns(conf.MaxIdleConns)
-	db.SetMaxOpenConns(conf.MaxOpenConns)
-	conn, err := Open(db, conf.DriverName)
+	ApplyPool(db, conf)
+	conn, err := OpenBy(db, conf)
 	if err != nil {
 		return err
 	}
 
 	database = conn
+	// a nil Retry keeps the policy set by SetRetryPolicy
+	if conf.Retry != nil {
+		retryPolicy.Store(conf.Retry)
+	}
 	logOpen = conf.LogOpened
 	if logOpen {
 		logger.Open(conf.LogMax)
@@ -105,21 +136,21 @@ func InsertBy(ctx context.Context, conn ksql.ConnectionInterface, table string,
 		op.Add(key, val)
 	})
 
-	return conn.Insert(ctx, op)
+	return _invalidate(ctx, conn, table)(conn.Insert(ctx, op))
 }
 
 func Insert(ctx context.Context, table string, data *Data) (int64, error) {
-	return InsertBy(ctx, database, table, data)
+	return InsertBy(ctx, _database(ctx), table, data)
 }
 
 func InsertFromBy(ctx context.Context, conn ksql.ConnectionInterface, table string, columns []string, query ksql.QueryInterface) (int64, error) {
 	op := NewInsert()
 	op.Table(table).Columns(columns...).From(query)
-	return conn.Insert(ctx, op)
+	return _invalidate(ctx, conn, table)(conn.Insert(ctx, op))
 }
 
 func InsertFrom(ctx context.Context, table string, columns []string, query ksql.QueryInterface) (int64, error) {
-	return InsertFromBy(ctx, database, table, columns, query)
+	return InsertFromBy(ctx, _database(ctx), table, columns, query)
 }
 
 func UpdateBy(ctx context.Context, conn ksql.ConnectionInterface, table string, data *Data, where ksql.WhereInterface) (int64, error) {
@@ -130,22 +161,68 @@ func UpdateBy(ctx context.Context, conn ksql.ConnectionInterface, table string,
 	})
 	op.Where(where)
 
-	return conn.Exec(ctx, op)
+	return _invalidate(ctx, conn, table)(conn.Exec(ctx, op))
 }
 
 func Update(ctx context.Context, table string, data *Data, where ksql.WhereInterface) (int64, error) {
-	return UpdateBy(ctx, database, table, data, where)
+	return UpdateBy(ctx, _database(ctx), table, data, where)
+}
+
+// update many rows in one statement, each column is set by CASE on keyColumn
+func UpdateCaseBy[K cmp.Ordered](ctx context.Context, conn ksql.ConnectionInterface, table, keyColumn string, rows map[K]*Data) (int64, error) {
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	keys := make([]K, 0, len(rows))
+	for key := range rows {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var columns []string
+	cases := make(map[string]ksql.CaseInterface)
+	for _, key := range keys {
+		rows[key].Range(func(column string, val any) {
+			c, ok := cases[column]
+			if !ok {
+				c = NewCaseOf(keyColumn).Assign(column)
+				cases[column] = c
+				columns = append(columns, column)
+			}
+			c.WhenValue(key, val)
+		})
+	}
+
+	op := NewUpdate()
+	op.Table(table)
+	for _, column := range columns {
+		op.SetExpress(cases[column].Else(Raw(quoteColumn(column))))
+	}
+	op.Where(NewWhere().In(keyColumn, ToList(keys)))
+
+	return _invalidate(ctx, conn, table)(conn.Exec(ctx, op))
+}
+
+func UpdateCase[K cmp.Ordered](ctx context.Context, table, keyColumn string, rows map[K]*Data) (int64, error) {
+	return UpdateCaseBy(ctx, _database(ctx), table, keyColumn, rows)
+}
+
+func quoteColumn(column string) string {
+	var builder strings.Builder
+	ks.Column(column, &builder)
+	return builder.String()
 }
 
 func DeleteBy(ctx context.Context, conn ksql.ConnectionInterface, table string, where ksql.WhereInterface) (int64, error) {
 	op := NewDelete()
 	op.Table(table).Where(where)
 
-	return conn.Exec(ctx, op)
+	return _invalidate(ctx, conn, table)(conn.Exec(ctx, op))
 }
 
 func Delete(ctx context.Context, table string, where ksql.WhereInterface) (int64, error) {
-	return DeleteBy(ctx, database, table, where)
+	return DeleteBy(ctx, _database(ctx), table, where)
 }
 
 func ExecBy(ctx context.Context, conn ksql.ConnectionInterface, op ksql.SqlInterface) (int64, error) {
@@ -153,15 +230,23 @@ func ExecBy(ctx context.Context, conn ksql.ConnectionInterface, op ksql.SqlInter
 }
 
 func Exec(ctx context.Context, op ksql.SqlInterface) (int64, error) {
-	return ExecBy(ctx, database, op)
+	return ExecBy(ctx, _database(ctx), op)
 }
 
 func QueryBy[T ksql.RowInterface](ctx context.Context, conn ksql.ConnectionInterface, op ksql.QueryInterface, models *[]T) error {
+	count := len(*models)
+	return _retry(ctx, conn, func() error {
+		*models = (*models)[:count]
+		return _queryBy(ctx, conn, op, models)
+	})
+}
+
+func _queryBy[T ksql.RowInterface](ctx context.Context, conn ksql.ConnectionInterface, op ksql.QueryInterface, models *[]T) error {
 	cc := NewContext(ctx)
 	cc.SqlLogStart(op)
 	defer cc.SqlLogEnd()
 
-	stmt, err := conn.Prepare(cc, op)
+	stmt, err := _prepare(cc, conn, op)
 	if err != nil {
 		return err
 	}
@@ -197,15 +282,21 @@ func QueryBy[T ksql.RowInterface](ctx context.Context, conn ksql.ConnectionInter
 }
 
 func Query[T ksql.RowInterface](ctx context.Context, op ksql.QueryInterface, models *[]T) error {
-	return QueryBy(ctx, database, op, models)
+	return QueryBy(ctx, _database(ctx), op, models)
 }
 
 func QueryRowBy[T ksql.RowInterface](ctx context.Context, conn ksql.ConnectionInterface, op ksql.QueryInterface, model T) error {
+	return _retry(ctx, conn, func() error {
+		return _queryRowBy(ctx, conn, op, model)
+	})
+}
+
+func _queryRowBy[T ksql.RowInterface](ctx context.Context, conn ksql.ConnectionInterface, op ksql.QueryInterface, model T) error {
 	cc := NewContext(ctx)
 	cc.SqlLogStart(op)
 	defer cc.SqlLogEnd()
 
-	stmt, err := conn.Prepare(ctx, op)
+	stmt, err := _prepare(ctx, conn, op)
 	if err != nil {
 		return err
 	}
@@ -218,7 +309,7 @@ func QueryRowBy[T ksql.RowInterface](ctx context.Context, conn ksql.ConnectionIn
 
 	if err := model.Scan(row, model); err != nil {
 		if err == sql.ErrNoRows {
-			return nil
+			return _err(_noRows(ctx), op)
 		}
 
 		return _err(err, op)
@@ -230,14 +321,17 @@ func QueryRowBy[T ksql.RowInterface](ctx context.Context, conn ksql.ConnectionIn
 }
 
 func QueryRow[T ksql.RowInterface](ctx context.Context, op ksql.QueryInterface, model T) error {
+	// a model read before the transaction of ctx still reads in it
 	if conn := model.Conn(); conn != nil {
-		return QueryRowBy(ctx, conn, op, model)
+		return QueryRowBy(ctx, ConnOf(ctx, conn), op, model)
 	}
-	return QueryRowBy(ctx, database, op, model)
+	return QueryRowBy(ctx, _database(ctx), op, model)
 }
 
+// the whole call is run again from the start when it fails with a retryable error,
+// call joins the transaction of ctx when there is one
 func TransactionBy(ctx context.Context, options *sql.TxOptions, call func(ctx context.Context, db ksql.ConnectionInterface) error) ksql.TxError {
-	return database.Clone().TransactionBy(ctx, options, call)
+	return TransactionWith(ctx, Propagation_Required, options, call)
 }
 
 func Transaction(ctx context.Context, call func(ctx context.Context, db ksql.ConnectionInterface) error) ksql.TxError {
@@ -245,7 +339,7 @@ func Transaction(ctx context.Context, call func(ctx context.Context, db ksql.Con
 }
 
 func Find[T FindType](ctx context.Context, model ksql.ModelInterface, id T) error {
-	return FindWith(ctx, database, model, id)
+	return FindWith(ctx, _database(ctx), model, id)
 }
 
 func FindWith[T FindType](ctx context.Context, conn ksql.ConnectionInterface, model ksql.ModelInterface, id T) error {
@@ -255,7 +349,7 @@ func FindWith[T FindType](ctx context.Context, conn ksql.ConnectionInterface, mo
 }
 
 func FindBy(ctx context.Context, model ksql.ModelInterface, call func(query ksql.QueryInterface)) error {
-	return FindByWith(ctx, database, model, call)
+	return FindByWith(ctx, _database(ctx), model, call)
 }
 
 func FindByWith(ctx context.Context, conn ksql.ConnectionInterface, model ksql.ModelInterface, call func(query ksql.QueryInterface)) error {
@@ -326,7 +420,7 @@ func DropTableBy(ctx context.Context, conn ksql.ConnectionInterface, table strin
 }
 
 func DropTable(ctx context.Context, table string) error {
-	return DropTableBy(ctx, database, table)
+	return DropTableBy(ctx, _database(ctx), table)
 }
 
 func DropTableIfExistsBy(ctx context.Context, conn ksql.ConnectionInterface, table string) error {
@@ -336,7 +430,7 @@ func DropTableIfExistsBy(ctx context.Context, conn ksql.ConnectionInterface, tab
 }
 
 func DropTableIfExists(ctx context.Context, table string) error {
-	return DropTableIfExistsBy(ctx, database, table)
+	return DropTableIfExistsBy(ctx, _database(ctx), table)
 }
 
 func ShowDDLBy(ctx context.Context, conn ksql.ConnectionInterface, table string) (string, error) {
@@ -350,7 +444,7 @@ func ShowDDLBy(ctx context.Context, conn ksql.ConnectionInterface, table string)
 }
 
 func ShowDDL(ctx context.Context, table string) (string, error) {
-	return ShowDDLBy(ctx, database, table)
+	return ShowDDLBy(ctx, _database(ctx), table)
 }
 
 func ScanBy(ctx context.Context, conn ksql.ConnectionInterface, query ksql.QueryInterface, vals ...any) error {
@@ -358,5 +452,5 @@ func ScanBy(ctx context.Context, conn ksql.ConnectionInterface, query ksql.Query
 }
 
 func Scan(ctx context.Context, query ksql.QueryInterface, vals ...any) error {
-	return ScanBy(ctx, database, query, vals...)
+	return ScanBy(ctx, _database(ctx), query, vals...)
 }
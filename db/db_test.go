@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateCase(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := Open(testDb, "mysql")
+
+	mock.ExpectPrepare("UPDATE `user` SET `age` = CASE `id` WHEN ? THEN ? WHEN ? THEN ? ELSE `age` END, `name` = CASE `id` WHEN ? THEN ? WHEN ? THEN ? ELSE `name` END, `balance` = CASE `id` WHEN ? THEN ? ELSE `balance` END WHERE `id` IN (?, ?, ?)").
+		ExpectExec().WithArgs(1, 18, 3, 30, 1, "kovey", 2, "bob", 3, 1.5, 1, 2, 3).WillReturnResult(sqlmock.NewResult(0, 3))
+	rows := map[int]*Data{
+		3: NewData().Set("age", 30).Set("balance", 1.5),
+		1: NewData().Set("age", 18).Set("name", "kovey"),
+		2: NewData().Set("name", "bob"),
+	}
+	count, err := UpdateCaseBy(context.Background(), conn, "user", "id", rows)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), count)
+	assert.Nil(t, mock.ExpectationsWereMet())
+
+	count, err = UpdateCaseBy[int](context.Background(), conn, "user", "id", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestQueryRawCall(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := Open(testDb, "mysql")
+	ctx := context.Background()
+	columns := newTestUser().Columns()
+
+	// a stored procedure may return result sets, CALL is read as a query and run as an exec
+	mock.ExpectPrepare("CALL adults(?)").ExpectQuery().WithArgs(18).WillReturnRows(sqlmock.NewRows(columns).AddRow(1, 19, "kovey", "2025-01-01 00:00:00", 1.5))
+	var users []*test_user
+	assert.Nil(t, QueryRawBy(ctx, conn, Raw("CALL adults(?)", 18), &users))
+	assert.Equal(t, 1, len(users))
+	assert.Equal(t, "kovey", users[0].Name)
+
+	mock.ExpectPrepare("CALL clean()").ExpectExec().WillReturnResult(sqlmock.NewResult(0, 2))
+	_, err := conn.ExecRaw(ctx, Raw("CALL clean()"))
+	assert.Nil(t, err)
+
+	err = QueryRawBy(ctx, conn, Raw("UPDATE user SET age = 1"), &users)
+	assert.ErrorIs(t, err, Err_Sql_Not_Query)
+
+	// prepared statements hold one statement
+	_, err = conn.ExecRaw(ctx, Raw("DELETE FROM user; DROP TABLE user"))
+	assert.ErrorIs(t, err, Err_Sql_Multi)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
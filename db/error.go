@@ -1,6 +1,98 @@
 package db
 
-import "fmt"
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+const (
+	Mysql_Err_Dup_Entry         uint16 = 1062
+	Mysql_Err_Lock_Wait_Timeout uint16 = 1205
+	Mysql_Err_Deadlock          uint16 = 1213
+	Mysql_Err_Fk_Referenced     uint16 = 1216
+	Mysql_Err_Fk_Referencing    uint16 = 1217
+	Mysql_Err_Option_Prevents   uint16 = 1290
+	Mysql_Err_Data_Too_Long     uint16 = 1406
+	Mysql_Err_Row_Referenced    uint16 = 1451
+	Mysql_Err_No_Referenced_Row uint16 = 1452
+	Mysql_Err_Dup_Entry_Key     uint16 = 1586
+	Mysql_Err_Read_Only_Tx      uint16 = 1792
+	Mysql_Err_Read_Only_Mode    uint16 = 1836
+)
+
+var (
+	Err_Duplicate_Key = errors.New("duplicate key")
+	Err_Foreign_Key   = errors.New("foreign key constraint fails")
+	Err_Deadlock      = errors.New("deadlock found")
+	Err_Lock_Timeout  = errors.New("lock wait timeout exceeded")
+	Err_Data_Too_Long = errors.New("data too long")
+	Err_No_Rows       = sql.ErrNoRows
+	Err_Not_Found     = fmt.Errorf("record not found: %w", sql.ErrNoRows)
+)
+
+var classes = map[uint16]error{
+	Mysql_Err_Dup_Entry:         Err_Duplicate_Key,
+	Mysql_Err_Dup_Entry_Key:     Err_Duplicate_Key,
+	Mysql_Err_Fk_Referenced:     Err_Foreign_Key,
+	Mysql_Err_Fk_Referencing:    Err_Foreign_Key,
+	Mysql_Err_Row_Referenced:    Err_Foreign_Key,
+	Mysql_Err_No_Referenced_Row: Err_Foreign_Key,
+	Mysql_Err_Deadlock:          Err_Deadlock,
+	Mysql_Err_Lock_Wait_Timeout: Err_Lock_Timeout,
+	Mysql_Err_Data_Too_Long:     Err_Data_Too_Long,
+}
+
+func errNumber(err error) (uint16, bool) {
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) {
+		return myErr.Number, true
+	}
+
+	return 0, false
+}
+
+// classify err to one of the Err_* sentinel errors, nil if unknown
+func Classify(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return Err_No_Rows
+	}
+
+	number, ok := errNumber(err)
+	if !ok {
+		return nil
+	}
+
+	return classes[number]
+}
+
+// key name and entry of a duplicate key error
+// e.g. Duplicate entry 'kovey' for key 'user.uk_name'
+func DuplicateKey(err error) (key string, entry string, ok bool) {
+	var myErr *mysql.MySQLError
+	if !errors.As(err, &myErr) || classes[myErr.Number] != Err_Duplicate_Key {
+		return "", "", false
+	}
+
+	msg := myErr.Message
+	if index := strings.LastIndex(msg, " for key '"); index >= 0 {
+		key = strings.TrimSuffix(msg[index+len(" for key '"):], "'")
+		msg = msg[:index]
+	}
+
+	if index := strings.Index(msg, "entry '"); index >= 0 {
+		entry = strings.TrimSuffix(msg[index+len("entry '"):], "'")
+	}
+
+	return key, entry, true
+}
 
 type SqlErr struct {
 	Sql   string
@@ -16,6 +108,15 @@ func (s *SqlErr) Error() string {
 	return fmt.Sprintf("sql: %s, binds: %v, error: %s", s.Sql, s.Binds, s.Err)
 }
 
+func (s *SqlErr) Unwrap() error {
+	return s.Err
+}
+
+func (s *SqlErr) Is(target error) bool {
+	class := Classify(s.Err)
+	return class != nil && class == target
+}
+
 type TxErr struct {
 	commitErr   error
 	rollbackErr error
@@ -39,9 +140,30 @@ func (t *TxErr) Call() error {
 	return t.callErr
 }
 
+func (t *TxErr) Unwrap() []error {
+	var errs []error
+	for _, err := range []error{t.beginErr, t.callErr, t.commitErr, t.rollbackErr} {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
 func (t *TxErr) Error() string {
 	return fmt.Sprintf(
 		"begin error: %s, call err: %s, commit error: %s, rollback error: %s",
 		t.beginErr, t.callErr, t.commitErr, t.rollbackErr,
 	)
 }
+
+// PanicErr is the call error of a transaction whose call panicked
+type PanicErr struct {
+	Value any
+	Stack []byte
+}
+
+func (p *PanicErr) Error() string {
+	return fmt.Sprintf("panic: %v", p.Value)
+}
@@ -148,8 +148,8 @@ func TestTransaction_Nested_SavepointRollback(t *testing.T) {
 	businessErr := errors.New("nested error")
 
 	mock.ExpectBegin()
-	mock.ExpectPrepare("SAVEPOINT ?").ExpectExec().WithArgs("trans_1").WillReturnResult(sqlmock.NewResult(0, 1))
-	mock.ExpectPrepare("ROLLBACK TO SAVEPOINT ?").ExpectExec().WithArgs("trans_1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("SAVEPOINT `trans_1`").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("ROLLBACK TO SAVEPOINT `trans_1`").WillReturnResult(sqlmock.NewResult(0, 1))
 	mock.ExpectRollback()
 
 	err := conn.Transaction(context.Background(), func(ctx context.Context, conn ksql.ConnectionInterface) error {
@@ -169,8 +169,8 @@ func TestTransaction_Nested_SavepointCommitError(t *testing.T) {
 	conn, _ := Open(testDb, "mysql")
 
 	mock.ExpectBegin()
-	mock.ExpectPrepare("SAVEPOINT ?").ExpectExec().WithArgs("trans_1").WillReturnResult(sqlmock.NewResult(0, 1))
-	mock.ExpectPrepare("RELEASE SAVEPOINT ?").ExpectExec().WithArgs("trans_1").WillReturnError(sqlmock.ErrCancelled)
+	mock.ExpectExec("SAVEPOINT `trans_1`").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("RELEASE SAVEPOINT `trans_1`").WillReturnError(sqlmock.ErrCancelled)
 	mock.ExpectRollback()
 
 	err := conn.Transaction(context.Background(), func(ctx context.Context, conn ksql.ConnectionInterface) error {
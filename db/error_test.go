@@ -1,9 +1,11 @@
 package db
 
 import (
+	"database/sql"
 	"errors"
 	"testing"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -28,3 +30,30 @@ func TestTxError(t *testing.T) {
 	assert.Equal(t, rollbackErr, txErr.Rollback())
 	assert.Equal(t, commitErr, txErr.Commit())
 }
+
+func TestSqlErrorClassify(t *testing.T) {
+	dup := &SqlErr{Sql: "INSERT INTO user (name) VALUES (?)", Binds: []any{"kovey"}, Err: &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'kovey' for key 'user.uk_name'"}}
+	assert.True(t, errors.Is(dup, Err_Duplicate_Key))
+	assert.False(t, errors.Is(dup, Err_Foreign_Key))
+	key, entry, ok := DuplicateKey(dup)
+	assert.True(t, ok)
+	assert.Equal(t, "user.uk_name", key)
+	assert.Equal(t, "kovey", entry)
+
+	var myErr *mysql.MySQLError
+	assert.True(t, errors.As(dup, &myErr))
+	assert.Equal(t, uint16(1062), myErr.Number)
+
+	assert.True(t, errors.Is(&SqlErr{Err: &mysql.MySQLError{Number: 1452}}, Err_Foreign_Key))
+	assert.True(t, errors.Is(&SqlErr{Err: &mysql.MySQLError{Number: 1213}}, Err_Deadlock))
+	assert.True(t, errors.Is(&SqlErr{Err: &mysql.MySQLError{Number: 1205}}, Err_Lock_Timeout))
+	assert.True(t, errors.Is(&SqlErr{Err: &mysql.MySQLError{Number: 1406}}, Err_Data_Too_Long))
+	assert.True(t, errors.Is(&SqlErr{Err: sql.ErrNoRows}, Err_No_Rows))
+	assert.False(t, errors.Is(&SqlErr{Err: errors.New("sql err")}, Err_Deadlock))
+	assert.Nil(t, Classify(errors.New("sql err")))
+	_, _, ok = DuplicateKey(&SqlErr{Err: &mysql.MySQLError{Number: 1213}})
+	assert.False(t, ok)
+
+	txErr := &TxErr{callErr: &SqlErr{Err: &mysql.MySQLError{Number: 1213}}}
+	assert.True(t, errors.Is(txErr, Err_Deadlock))
+}
@@ -7,6 +7,7 @@ import (
 
 type NewQueryFun func() ksql.QueryInterface
 type RawFun func(raw string, binds ...any) ksql.ExpressInterface
+type RawNamedFun func(raw string, params any) (ksql.ExpressInterface, error)
 type NewInsertFun func() ksql.InsertInterface
 type NewUpdateFun func() ksql.UpdateInterface
 type NewDeleteFun func() ksql.DeleteInterface
@@ -17,6 +18,13 @@ type NewAlterTableFun func() ksql.AlterInterface
 type NewTableFun func() ksql.TableInterface
 type NewWhereFun func() ksql.WhereInterface
 type NewDoFun func() ksql.DoInterface
+type NewCaseFun func() ksql.CaseInterface
+type NewCaseOfFun func(column string) ksql.CaseInterface
+type NewWindowFun func() ksql.WindowInterface
+type NewWindowFuncFun func(fun string, column string, args ...any) ksql.WindowFuncInterface
+type WindowRankFun func() ksql.WindowFuncInterface
+type WindowOffsetFun func(column string, offset uint, def ...any) ksql.WindowFuncInterface
+type WindowColumnFun func(column string) ksql.WindowFuncInterface
 
 var NewWhere NewWhereFun = func() ksql.WhereInterface {
 	return sql.NewWhere()
@@ -25,6 +33,7 @@ var NewQuery NewQueryFun = func() ksql.QueryInterface {
 	return sql.NewQuery()
 }
 var Raw RawFun = sql.Raw
+var RawNamed RawNamedFun = sql.RawNamed
 var NewInsert NewInsertFun = func() ksql.InsertInterface {
 	return sql.NewInsert()
 }
@@ -52,6 +61,38 @@ var NewTable NewTableFun = func() ksql.TableInterface {
 var NewDo NewDoFun = func() ksql.DoInterface {
 	return sql.NewDo()
 }
+var NewCase NewCaseFun = func() ksql.CaseInterface {
+	return sql.NewCase()
+}
+var NewCaseOf NewCaseOfFun = func(column string) ksql.CaseInterface {
+	return sql.NewCaseOf(column)
+}
+var NewWindow NewWindowFun = func() ksql.WindowInterface {
+	return sql.NewWindow()
+}
+var NewWindowFunc NewWindowFuncFun = func(fun string, column string, args ...any) ksql.WindowFuncInterface {
+	return sql.NewWindowFunc(fun, column, args...)
+}
+var RowNumber WindowRankFun = func() ksql.WindowFuncInterface {
+	return sql.RowNumber()
+}
+var Rank WindowRankFun = func() ksql.WindowFuncInterface {
+	return sql.Rank()
+}
+var DenseRank WindowRankFun = func() ksql.WindowFuncInterface {
+	return sql.DenseRank()
+}
+var Lag WindowOffsetFun = func(column string, offset uint, def ...any) ksql.WindowFuncInterface {
+	return sql.Lag(column, offset, def...)
+}
+var Lead WindowOffsetFun = func(column string, offset uint, def ...any) ksql.WindowFuncInterface {
+	return sql.Lead(column, offset, def...)
+}
+var SumOver WindowColumnFun = func(column string) ksql.WindowFuncInterface {
+	return sql.SumOver(column)
+}
+var Preceding = sql.Preceding
+var Following = sql.Following
 
 func ToList[T any](data []T) []any {
 	tmp := make([]any, len(data))
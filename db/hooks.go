@@ -0,0 +1,104 @@
+package db
+
+import (
+	"context"
+)
+
+type txHooks struct {
+	commits   []func()
+	rollbacks []func()
+}
+
+func (t *txHooks) merge(other *txHooks) {
+	if t == nil || other == nil {
+		return
+	}
+
+	t.commits = append(t.commits, other.commits...)
+	t.rollbacks = append(t.rollbacks, other.rollbacks...)
+}
+
+func (t *txHooks) committed() {
+	if t == nil {
+		return
+	}
+
+	for _, call := range t.commits {
+		call()
+	}
+}
+
+func (t *txHooks) rolledBack() {
+	if t == nil {
+		return
+	}
+
+	for _, call := range t.rollbacks {
+		call()
+	}
+}
+
+func (c *Connection) _topHooks() *txHooks {
+	if len(c.hooks) == 0 {
+		return nil
+	}
+
+	return c.hooks[len(c.hooks)-1]
+}
+
+func (c *Connection) _popHooks() *txHooks {
+	hooks := c._topHooks()
+	if hooks != nil {
+		c.hooks = c.hooks[:len(c.hooks)-1]
+	}
+
+	return hooks
+}
+
+// OnCommit runs call once the transaction is committed, at once when not in a transaction,
+// calls registered inside a savepoint are dropped when it is rolled back
+func (c *Connection) OnCommit(call func()) {
+	hooks := c._topHooks()
+	if c.tx == nil || hooks == nil {
+		call()
+		return
+	}
+
+	hooks.commits = append(hooks.commits, call)
+}
+
+// OnRollback runs call once the transaction or the current savepoint is rolled back, never when not in a transaction
+func (c *Connection) OnRollback(call func()) {
+	hooks := c._topHooks()
+	if c.tx == nil || hooks == nil {
+		return
+	}
+
+	hooks.rollbacks = append(hooks.rollbacks, call)
+}
+
+type txHookConnection interface {
+	OnCommit(call func())
+	OnRollback(call func())
+}
+
+// OnCommit registers call on the transaction of ctx, call runs at once when ctx has no transaction
+func OnCommit(ctx context.Context, call func()) {
+	if tx, ok := TxFrom(ctx); ok {
+		if h, ok := tx.(txHookConnection); ok {
+			h.OnCommit(call)
+			return
+		}
+	}
+
+	call()
+}
+
+// OnRollback registers call on the transaction of ctx, nothing happens when ctx has no transaction
+func OnRollback(ctx context.Context, call func()) {
+	if tx, ok := TxFrom(ctx); ok {
+		if h, ok := tx.(txHookConnection); ok {
+			h.OnRollback(call)
+		}
+	}
+}
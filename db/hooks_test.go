@@ -0,0 +1,92 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	ksql "github.com/kovey/db-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionPanic(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := Open(testDb, "mysql")
+
+	var events []string
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	assert.PanicsWithValue(t, "boom", func() {
+		conn.Transaction(context.Background(), func(ctx context.Context, tx ksql.ConnectionInterface) error {
+			OnCommit(ctx, func() { events = append(events, "commit") })
+			OnRollback(ctx, func() { events = append(events, "rollback") })
+			panic("boom")
+		})
+	})
+	assert.False(t, conn.InTransaction())
+	assert.Equal(t, []string{"rollback"}, events)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactionRecoverPanic(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := OpenBy(testDb, Config{DriverName: "mysql", RecoverPanic: true})
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	err := conn.Clone().Transaction(context.Background(), func(ctx context.Context, tx ksql.ConnectionInterface) error {
+		var m map[string]int
+		m["a"] = 1
+		return nil
+	})
+	var panicErr *PanicErr
+	assert.True(t, errors.As(err, &panicErr))
+	assert.Contains(t, panicErr.Error(), "assignment to entry in nil map")
+	assert.NotEmpty(t, panicErr.Stack)
+	assert.Nil(t, err.Rollback())
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactionHooks(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := Open(testDb, "mysql")
+
+	var events []string
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectCommit().WillReturnError(errors.New("connection lost"))
+
+	err := conn.Transaction(context.Background(), func(ctx context.Context, tx ksql.ConnectionInterface) error {
+		OnCommit(ctx, func() { events = append(events, "commit 1") })
+		OnRollback(ctx, func() { events = append(events, "rollback 1") })
+		assert.Empty(t, events)
+		return nil
+	})
+	assert.Nil(t, err)
+
+	err = conn.Transaction(context.Background(), func(ctx context.Context, tx ksql.ConnectionInterface) error {
+		tx.(*Connection).OnCommit(func() { events = append(events, "commit 2") })
+		tx.(*Connection).OnRollback(func() { events = append(events, "rollback 2") })
+		return errors.New("failed")
+	})
+	assert.NotNil(t, err)
+
+	err = conn.Transaction(context.Background(), func(ctx context.Context, tx ksql.ConnectionInterface) error {
+		OnCommit(ctx, func() { events = append(events, "commit 3") })
+		OnRollback(ctx, func() { events = append(events, "rollback 3") })
+		return nil
+	})
+	assert.NotNil(t, err.Commit())
+
+	OnCommit(context.Background(), func() { events = append(events, "no transaction") })
+	OnRollback(context.Background(), func() { events = append(events, "never") })
+	assert.Equal(t, []string{"commit 1", "rollback 2", "rollback 3", "no transaction"}, events)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
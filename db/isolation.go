@@ -0,0 +1,43 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	ksql "github.com/kovey/db-go/v3"
+)
+
+// ReadOnlyTx runs call in a read only transaction of the global connection,
+// it always begins a new transaction so the option is not lost inside the transaction of ctx
+func ReadOnlyTx(ctx context.Context, call func(ctx context.Context, db ksql.ConnectionInterface) error) ksql.TxError {
+	return TransactionWith(ctx, Propagation_Requires_New, &sql.TxOptions{ReadOnly: true}, call)
+}
+
+// SerializableTx runs call in a serializable transaction of the global connection,
+// it always begins a new transaction so the isolation level is not lost inside the transaction of ctx
+func SerializableTx(ctx context.Context, call func(ctx context.Context, db ksql.ConnectionInterface) error) ksql.TxError {
+	return TransactionWith(ctx, Propagation_Requires_New, &sql.TxOptions{Isolation: sql.LevelSerializable}, call)
+}
+
+// WithLockWaitTimeout runs call in a transaction whose session innodb_lock_wait_timeout is sec seconds,
+// the previous value is restored before the transaction ends so the pooled connection is left untouched
+func WithLockWaitTimeout(ctx context.Context, sec uint, call func(ctx context.Context, db ksql.ConnectionInterface) error) ksql.TxError {
+	return Transaction(ctx, func(ctx context.Context, tx ksql.ConnectionInterface) (err error) {
+		var old uint64
+		if err = tx.ScanRaw(ctx, Raw("SELECT @@SESSION.innodb_lock_wait_timeout"), &old); err != nil {
+			return err
+		}
+
+		if _, err = tx.ExecRaw(ctx, Raw("SET SESSION innodb_lock_wait_timeout = ?", sec)); err != nil {
+			return err
+		}
+
+		defer func() {
+			if _, restoreErr := tx.ExecRaw(ctx, Raw("SET SESSION innodb_lock_wait_timeout = ?", old)); restoreErr != nil && err == nil {
+				err = restoreErr
+			}
+		}()
+
+		return call(ctx, tx)
+	})
+}
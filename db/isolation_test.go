@@ -0,0 +1,86 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	ksql "github.com/kovey/db-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadOnlyAndSerializableTx(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := Open(testDb, "mysql")
+	database = conn
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	assert.Nil(t, ReadOnlyTx(context.Background(), func(ctx context.Context, db ksql.ConnectionInterface) error {
+		assert.True(t, db.InTransaction())
+		return nil
+	}))
+	failed := errors.New("failed")
+	err := SerializableTx(context.Background(), func(ctx context.Context, db ksql.ConnectionInterface) error {
+		return failed
+	})
+	assert.Equal(t, failed, err.Call())
+	assert.Nil(t, mock.ExpectationsWereMet())
+
+	// the transaction of ctx is not joined, the read only one begins on its own
+	mock.ExpectBegin()
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	mock.ExpectCommit()
+	assert.Nil(t, Transaction(context.Background(), func(ctx context.Context, outer ksql.ConnectionInterface) error {
+		return ReadOnlyTx(ctx, func(ctx context.Context, db ksql.ConnectionInterface) error {
+			assert.True(t, db.InTransaction())
+			assert.NotSame(t, outer, db)
+			found, _ := TxFrom(ctx)
+			assert.Equal(t, db, found)
+			return nil
+		})
+	}))
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestWithLockWaitTimeout(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := Open(testDb, "mysql")
+	database = conn
+
+	failed := errors.New("failed")
+	for _, callErr := range []error{nil, failed} {
+		mock.ExpectBegin()
+		mock.ExpectPrepare("SELECT @@SESSION.innodb_lock_wait_timeout").ExpectQuery().WillReturnRows(sqlmock.NewRows([]string{"timeout"}).AddRow(50))
+		mock.ExpectPrepare("SET SESSION innodb_lock_wait_timeout = ?").ExpectExec().WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectPrepare("DELETE FROM `user` WHERE `id` = ?").ExpectExec().WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectPrepare("SET SESSION innodb_lock_wait_timeout = ?").ExpectExec().WithArgs(50).WillReturnResult(sqlmock.NewResult(0, 0))
+		if callErr == nil {
+			mock.ExpectCommit()
+		} else {
+			mock.ExpectRollback()
+		}
+
+		err := WithLockWaitTimeout(context.Background(), 3, func(ctx context.Context, db ksql.ConnectionInterface) error {
+			if _, err := Delete(ctx, "user", NewWhere().Where("id", ksql.Eq, 1)); err != nil {
+				return err
+			}
+
+			return callErr
+		})
+		if callErr == nil {
+			assert.Nil(t, err)
+		} else {
+			assert.Equal(t, failed, err.Call())
+		}
+	}
+
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
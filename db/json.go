@@ -0,0 +1,48 @@
+package db
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// JSON column decoded into T, NULL scans into the zero value of T
+type JSON[T any] struct {
+	Data T
+}
+
+func NewJSON[T any](data T) JSON[T] {
+	return JSON[T]{Data: data}
+}
+
+func (j *JSON[T]) Scan(src any) error {
+	var zero T
+	j.Data = zero
+	switch tmp := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(tmp, &j.Data)
+	case string:
+		return json.Unmarshal([]byte(tmp), &j.Data)
+	default:
+		return fmt.Errorf("json: unsupported scan type %T", src)
+	}
+}
+
+func (j JSON[T]) Value() (driver.Value, error) {
+	buf, err := json.Marshal(j.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(buf), nil
+}
+
+func (j JSON[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(j.Data)
+}
+
+func (j *JSON[T]) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &j.Data)
+}
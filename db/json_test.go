@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	ksql "github.com/kovey/db-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+type test_profile struct {
+	Tags  []string `json:"tags"`
+	Level int      `json:"level"`
+}
+
+type test_json_row struct {
+	*Row
+	Id      int64
+	Profile JSON[test_profile]
+}
+
+func (t *test_json_row) Clone() ksql.RowInterface { return &test_json_row{Row: &Row{}} }
+func (t *test_json_row) Columns() []string        { return []string{"id", "profile"} }
+func (t *test_json_row) Values() []any            { return []any{&t.Id, &t.Profile} }
+
+func TestJSON(t *testing.T) {
+	var j JSON[test_profile]
+	assert.Nil(t, j.Scan([]byte(`{"tags":["a","b"],"level":3}`)))
+	assert.Equal(t, test_profile{Tags: []string{"a", "b"}, Level: 3}, j.Data)
+	assert.Nil(t, j.Scan(nil))
+	assert.Equal(t, test_profile{}, j.Data)
+	assert.NotNil(t, j.Scan(1))
+
+	value, err := NewJSON(map[string]int{"a": 1}).Value()
+	assert.Nil(t, err)
+	assert.Equal(t, `{"a":1}`, value)
+
+	buf, err := json.Marshal(struct {
+		P JSON[[]int] `json:"p"`
+	}{P: NewJSON([]int{1, 2})})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"p":[1,2]}`, string(buf))
+}
+
+func TestJSONQuery(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := Open(testDb, "mysql")
+
+	mock.ExpectPrepare("SELECT `id`, `profile` FROM `user` WHERE  (JSON_CONTAINS(`profile`, ?, ?) AND JSON_EXTRACT(`profile`, ?) > ?)").
+		ExpectQuery().WithArgs(`"a"`, "$.tags", "$.level", 2).WillReturnRows(sqlmock.NewRows([]string{"id", "profile"}).AddRow(1, `{"tags":["a"],"level":3}`))
+	var rows []*test_json_row
+	err := Rows(&rows).WithConn(conn).Table("user").Columns("id", "profile").AndWhere(func(w ksql.WhereInterface) {
+		w.JsonContains("profile", "$.tags", "a").JsonExtract("profile", "$.level", ksql.Gt, 2)
+	}).All(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(rows))
+	assert.Equal(t, 3, rows[0].Profile.Data.Level)
+
+	mock.ExpectPrepare("INSERT INTO `user` (`profile`) VALUES (?)").ExpectExec().WithArgs(`{"tags":null,"level":1}`).WillReturnResult(sqlmock.NewResult(2, 1))
+	id, err := InsertBy(context.Background(), conn, "user", NewData().Set("profile", NewJSON(test_profile{Level: 1})))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), id)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
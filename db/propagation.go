@@ -0,0 +1,88 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	ksql "github.com/kovey/db-go/v3"
+)
+
+var Err_Transaction_Exists = errors.New("transaction exists")
+
+type Propagation byte
+
+const (
+	Propagation_Required     Propagation = 0 // join the transaction of ctx, begin one when there is none
+	Propagation_Requires_New Propagation = 1 // always begin a new transaction on another connection
+	Propagation_Nested       Propagation = 2 // savepoint inside the transaction of ctx, begin one when there is none
+	Propagation_Never        Propagation = 3 // fail with Err_Transaction_Exists when ctx has a transaction
+)
+
+type txConnKey struct{}
+
+// WithTx stores the transaction connection in ctx, db, model and builder calls with ctx run on it
+func WithTx(ctx context.Context, conn ksql.ConnectionInterface) context.Context {
+	return context.WithValue(ctx, txConnKey{}, conn)
+}
+
+// TxFrom returns the active transaction connection of ctx
+func TxFrom(ctx context.Context) (ksql.ConnectionInterface, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+
+	conn, ok := ctx.Value(txConnKey{}).(ksql.ConnectionInterface)
+	if !ok || conn == nil || !conn.InTransaction() {
+		return nil, false
+	}
+
+	return conn, true
+}
+
+// ConnOf returns the transaction connection of ctx when it was begun on the database of conn or conn is nil,
+// conn otherwise, so a connection to another database or shard node never runs in the transaction of ctx
+func ConnOf(ctx context.Context, conn ksql.ConnectionInterface) ksql.ConnectionInterface {
+	if tx, ok := TxFrom(ctx); ok && (conn == nil || conn.Database() == tx.Database()) {
+		return tx
+	}
+
+	return conn
+}
+
+func _database(ctx context.Context) ksql.ConnectionInterface {
+	return ConnOf(ctx, database)
+}
+
+// run call in a transaction of the global connection by propagation
+func TransactionWith(ctx context.Context, propagation Propagation, options *sql.TxOptions, call func(ctx context.Context, db ksql.ConnectionInterface) error) ksql.TxError {
+	tx, ok := TxFrom(ctx)
+	switch propagation {
+	case Propagation_Never:
+		if ok {
+			return &TxErr{beginErr: Err_Transaction_Exists}
+		}
+
+		if err := call(ctx, database); err != nil {
+			return &TxErr{callErr: err}
+		}
+
+		return nil
+	case Propagation_Required:
+		if ok {
+			if err := call(ctx, tx); err != nil {
+				return &TxErr{callErr: err}
+			}
+
+			return nil
+		}
+	case Propagation_Nested:
+		if ok {
+			return tx.TransactionBy(ctx, options, call)
+		}
+	}
+
+	return _retryTx(ctx, database, func() ksql.TxError {
+		return database.Clone().TransactionBy(ctx, options, call)
+	})
+}
@@ -0,0 +1,141 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	ksql "github.com/kovey/db-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+type propagation_user struct {
+	Id   int
+	Name string
+	conn ksql.ConnectionInterface
+}
+
+func (p *propagation_user) Values() []any                          { return []any{&p.Id, &p.Name} }
+func (p *propagation_user) Clone() ksql.RowInterface               { return &propagation_user{} }
+func (p *propagation_user) WithConn(conn ksql.ConnectionInterface) { p.conn = conn }
+func (p *propagation_user) Conn() ksql.ConnectionInterface         { return p.conn }
+func (p *propagation_user) Sharding(ksql.Sharding)                 {}
+func (p *propagation_user) Scan(s ksql.ScanInterface, r ksql.RowInterface) error {
+	return s.Scan(r.Values()...)
+}
+
+func TestTransactionContext(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	otherDb, otherMock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer otherDb.Close()
+	conn, _ := Open(testDb, "mysql")
+	other, _ := Open(otherDb, "mysql")
+	same, _ := Open(testDb, "mysql")
+	database = conn
+
+	mock.ExpectBegin()
+	mock.ExpectPrepare("UPDATE `user` SET `name` = ? WHERE `id` = ?").ExpectExec().WithArgs("kovey", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare("SELECT `id`, `name` FROM `user` WHERE `id` = ? FOR UPDATE").ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "kovey"))
+	otherMock.ExpectPrepare("SELECT `id`, `name` FROM `user` WHERE `id` = ?").ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "other"))
+	mock.ExpectPrepare("DELETE FROM `user` WHERE `id` = ?").ExpectExec().WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := Transaction(context.Background(), func(ctx context.Context, tx ksql.ConnectionInterface) error {
+		found, ok := TxFrom(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, tx, found)
+		if _, err := Update(ctx, "user", NewData().Set("name", "kovey"), NewWhere().Where("id", ksql.Eq, 1)); err != nil {
+			return err
+		}
+
+		// a connection to the database of the transaction joins it
+		user := &propagation_user{conn: same}
+		if err := Build(user).Table("user").Columns("id", "name").Where("id", ksql.Eq, 1).ForUpdate().First(ctx); err != nil {
+			return err
+		}
+		assert.Equal(t, "kovey", user.Name)
+		assert.Equal(t, tx, user.Conn())
+
+		// a connection to another database keeps running on it
+		remote := &propagation_user{conn: other}
+		if err := Build(remote).Table("user").Columns("id", "name").Where("id", ksql.Eq, 1).First(ctx); err != nil {
+			return err
+		}
+		assert.Equal(t, "other", remote.Name)
+		assert.Equal(t, other, remote.Conn())
+
+		return Transaction(ctx, func(ctx context.Context, inner ksql.ConnectionInterface) error {
+			assert.Equal(t, tx, inner)
+			_, err := Delete(ctx, "user", NewWhere().Where("id", ksql.Eq, 2))
+			return err
+		})
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+	assert.Nil(t, otherMock.ExpectationsWereMet())
+
+	_, ok := TxFrom(context.Background())
+	assert.False(t, ok)
+	assert.Equal(t, other, ConnOf(context.Background(), other))
+}
+
+func TestQueryRowInTransaction(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := Open(testDb, "mysql")
+	database = conn
+
+	mock.ExpectPrepare("SELECT `id`, `name` FROM `user` WHERE `id` = ?").ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "kovey"))
+	mock.ExpectBegin()
+	mock.ExpectPrepare("SELECT `id`, `name` FROM `user` WHERE `id` = ?").ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "bob"))
+	mock.ExpectCommit()
+
+	// the model keeps the connection it was read with before the transaction
+	user := &propagation_user{}
+	assert.Nil(t, QueryRow(context.Background(), NewQuery().Table("user").Columns("id", "name").Where("id", ksql.Eq, 1), user))
+	assert.Equal(t, conn, user.Conn())
+
+	err := Transaction(context.Background(), func(ctx context.Context, tx ksql.ConnectionInterface) error {
+		if err := QueryRow(ctx, NewQuery().Table("user").Columns("id", "name").Where("id", ksql.Eq, 1), user); err != nil {
+			return err
+		}
+
+		assert.Equal(t, "bob", user.Name)
+		assert.Same(t, tx, user.Conn())
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactionPropagationNever(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := Open(testDb, "mysql")
+	database = conn
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	callErr := errors.New("call error")
+	err := Transaction(context.Background(), func(ctx context.Context, tx ksql.ConnectionInterface) error {
+		txErr := TransactionWith(ctx, Propagation_Never, nil, func(ctx context.Context, db ksql.ConnectionInterface) error {
+			return nil
+		})
+		assert.Equal(t, Err_Transaction_Exists, txErr.Begin())
+
+		txErr = TransactionWith(ctx, Propagation_Required, nil, func(ctx context.Context, db ksql.ConnectionInterface) error {
+			return callErr
+		})
+		return txErr.Call()
+	})
+	assert.Equal(t, callErr, err.Call())
+
+	err = TransactionWith(context.Background(), Propagation_Never, nil, func(ctx context.Context, db ksql.ConnectionInterface) error {
+		assert.False(t, db.InTransaction())
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
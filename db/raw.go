@@ -14,8 +14,24 @@ var (
 	Err_Sql_Not_Insert = errors.New("sql not insert")
 	Err_Sql_Not_Query  = errors.New("sql not query")
 	Err_Sql_Not_Exec   = errors.New("sql not exec")
+	Err_Sql_Multi      = errors.New("sql multi statements")
 )
 
+// multiExpress is implemented by raw statements that know whether they hold more than one statement
+type multiExpress interface {
+	IsMulti() bool
+}
+
+// CALL, table maintenance and HANDLER run as an exec and may return result sets, so they are also read as a query
+func _isQuery(raw ksql.ExpressInterface) bool {
+	switch raw.Type() {
+	case ksql.Sql_Type_Call, ksql.Sql_Type_Maintenance, ksql.Sql_Type_Handler:
+		return true
+	}
+
+	return !raw.IsExec()
+}
+
 func InsertRawBy(ctx context.Context, conn ksql.ConnectionInterface, raw ksql.ExpressInterface) (int64, error) {
 	if raw.Type() != ksql.Sql_Type_Insert {
 		return 0, _errRaw(Err_Sql_Not_Insert, raw)
@@ -31,7 +47,7 @@ func InsertRawBy(ctx context.Context, conn ksql.ConnectionInterface, raw ksql.Ex
 }
 
 func InsertRaw(ctx context.Context, raw ksql.ExpressInterface) (int64, error) {
-	return InsertRawBy(ctx, database, raw)
+	return InsertRawBy(ctx, _database(ctx), raw)
 }
 
 func UpdateRawBy(ctx context.Context, conn ksql.ConnectionInterface, raw ksql.ExpressInterface) (int64, error) {
@@ -49,7 +65,7 @@ func UpdateRawBy(ctx context.Context, conn ksql.ConnectionInterface, raw ksql.Ex
 }
 
 func UpdateRaw(ctx context.Context, raw ksql.ExpressInterface) (int64, error) {
-	return UpdateRawBy(ctx, database, raw)
+	return UpdateRawBy(ctx, _database(ctx), raw)
 }
 
 func DeleteRawBy(ctx context.Context, conn ksql.ConnectionInterface, raw ksql.ExpressInterface) (int64, error) {
@@ -67,19 +83,27 @@ func DeleteRawBy(ctx context.Context, conn ksql.ConnectionInterface, raw ksql.Ex
 }
 
 func DeleteRaw(ctx context.Context, raw ksql.ExpressInterface) (int64, error) {
-	return DeleteRawBy(ctx, database, raw)
+	return DeleteRawBy(ctx, _database(ctx), raw)
 }
 
 func QueryRawBy[T ksql.RowInterface](ctx context.Context, conn ksql.ConnectionInterface, raw ksql.ExpressInterface, models *[]T) error {
-	if raw.IsExec() {
+	if !_isQuery(raw) {
 		return _errRaw(Err_Sql_Not_Query, raw)
 	}
 
+	count := len(*models)
+	return _retry(ctx, conn, func() error {
+		*models = (*models)[:count]
+		return _queryRawBy(ctx, conn, raw, models)
+	})
+}
+
+func _queryRawBy[T ksql.RowInterface](ctx context.Context, conn ksql.ConnectionInterface, raw ksql.ExpressInterface, models *[]T) error {
 	cc := NewContext(ctx)
 	cc.RawSqlLogStart(raw)
 	defer cc.SqlLogEnd()
 
-	stmt, err := conn.PrepareRaw(cc, raw)
+	stmt, err := _prepareRaw(cc, conn, raw)
 	if err != nil {
 		return err
 	}
@@ -115,19 +139,25 @@ func QueryRawBy[T ksql.RowInterface](ctx context.Context, conn ksql.ConnectionIn
 }
 
 func QueryRaw[T ksql.RowInterface](ctx context.Context, raw ksql.ExpressInterface, models *[]T) error {
-	return QueryRawBy(ctx, database, raw, models)
+	return QueryRawBy(ctx, _database(ctx), raw, models)
 }
 
 func QueryRowRawBy[T ksql.RowInterface](ctx context.Context, conn ksql.ConnectionInterface, raw ksql.ExpressInterface, model T) error {
-	if raw.IsExec() {
+	if !_isQuery(raw) {
 		return _errRaw(Err_Sql_Not_Query, raw)
 	}
 
+	return _retry(ctx, conn, func() error {
+		return _queryRowRawBy(ctx, conn, raw, model)
+	})
+}
+
+func _queryRowRawBy[T ksql.RowInterface](ctx context.Context, conn ksql.ConnectionInterface, raw ksql.ExpressInterface, model T) error {
 	cc := NewContext(ctx)
 	cc.RawSqlLogStart(raw)
 	defer cc.SqlLogEnd()
 
-	stmt, err := conn.PrepareRaw(cc, raw)
+	stmt, err := _prepareRaw(cc, conn, raw)
 	if err != nil {
 		return err
 	}
@@ -141,7 +171,7 @@ func QueryRowRawBy[T ksql.RowInterface](ctx context.Context, conn ksql.Connectio
 	if err := model.Scan(row, model); err != nil {
 		if err == sql.ErrNoRows {
 			model.WithConn(conn)
-			return nil
+			return _errRaw(_noRows(ctx), raw)
 		}
 
 		return _errRaw(err, raw)
@@ -152,15 +182,25 @@ func QueryRowRawBy[T ksql.RowInterface](ctx context.Context, conn ksql.Connectio
 }
 
 func QueryRowRaw[T ksql.RowInterface](ctx context.Context, raw ksql.ExpressInterface, model T) error {
-	return QueryRowRawBy(ctx, database, raw, model)
+	return QueryRowRawBy(ctx, _database(ctx), raw, model)
 }
 
 func _hasRaw(ctx context.Context, conn ksql.ConnectionInterface, raw ksql.ExpressInterface) (bool, error) {
+	var has bool
+	err := _guarded(ctx, conn, func() error {
+		var err error
+		has, err = _hasRawOnce(ctx, conn, raw)
+		return err
+	})
+	return has, err
+}
+
+func _hasRawOnce(ctx context.Context, conn ksql.ConnectionInterface, raw ksql.ExpressInterface) (bool, error) {
 	cc := NewContext(ctx)
 	cc.RawSqlLogStart(raw)
 	defer cc.SqlLogEnd()
 
-	stmt, err := conn.PrepareRaw(cc, raw)
+	stmt, err := _prepareRaw(cc, conn, raw)
 	if err != nil {
 		return false, err
 	}
@@ -181,7 +221,7 @@ func HasTableBy(ctx context.Context, conn ksql.ConnectionInterface, table string
 }
 
 func HasTable(ctx context.Context, table string) (bool, error) {
-	return HasTableBy(ctx, database, table)
+	return HasTableBy(ctx, _database(ctx), table)
 }
 
 func HasColumnBy(ctx context.Context, conn ksql.ConnectionInterface, table, column string) (bool, error) {
@@ -190,7 +230,7 @@ func HasColumnBy(ctx context.Context, conn ksql.ConnectionInterface, table, colu
 }
 
 func HasColumn(ctx context.Context, table, column string) (bool, error) {
-	return HasColumnBy(ctx, database, table, column)
+	return HasColumnBy(ctx, _database(ctx), table, column)
 }
 
 func HasIndexBy(ctx context.Context, conn ksql.ConnectionInterface, table, index string) (bool, error) {
@@ -199,11 +239,11 @@ func HasIndexBy(ctx context.Context, conn ksql.ConnectionInterface, table, index
 }
 
 func HasIndex(ctx context.Context, table, index string) (bool, error) {
-	return HasIndexBy(ctx, database, table, index)
+	return HasIndexBy(ctx, _database(ctx), table, index)
 }
 
 func ExecRaw(ctx context.Context, raw ksql.ExpressInterface) (sql.Result, error) {
-	return database.ExecRaw(ctx, raw)
+	return _database(ctx).ExecRaw(ctx, raw)
 }
 
 func ExecRawBy(ctx context.Context, conn ksql.ConnectionInterface, raw ksql.ExpressInterface) (sql.Result, error) {
@@ -220,5 +260,5 @@ func DoBy(ctx context.Context, conn ksql.ConnectionInterface, raws ...ksql.Expre
 }
 
 func Do(ctx context.Context, raws ...ksql.ExpressInterface) (int64, error) {
-	return DoBy(ctx, database, raws...)
+	return DoBy(ctx, _database(ctx), raws...)
 }
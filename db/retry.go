@@ -0,0 +1,165 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	ksql "github.com/kovey/db-go/v3"
+)
+
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	// fraction of the delay randomized, 0 disables jitter
+	Jitter float64
+	// mysql error numbers treated as transient, nil uses the defaults
+	Numbers []uint16
+	// overrides the classification by error number when set
+	Retryable func(err error) bool
+}
+
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3, BaseDelay: 10 * time.Millisecond, MaxDelay: time.Second, Jitter: 0.5,
+		Numbers: []uint16{Mysql_Err_Lock_Wait_Timeout, Mysql_Err_Deadlock, Mysql_Err_Option_Prevents, Mysql_Err_Read_Only_Tx, Mysql_Err_Read_Only_Mode},
+	}
+}
+
+var retryPolicy atomic.Pointer[RetryPolicy]
+
+// set global retry policy, nil disables retrying of connections opened without their own policy
+func SetRetryPolicy(policy *RetryPolicy) {
+	retryPolicy.Store(policy)
+}
+
+type retryConnection interface {
+	_retryPolicy() *RetryPolicy
+}
+
+// the retry policy of conn, the global one when conn has none
+func _policy(conn ksql.ConnectionInterface) *RetryPolicy {
+	if c, ok := conn.(retryConnection); ok {
+		if policy := c._retryPolicy(); policy != nil {
+			return policy
+		}
+	}
+
+	return retryPolicy.Load()
+}
+
+func (r *RetryPolicy) IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if r.Retryable != nil {
+		return r.Retryable(err)
+	}
+
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn) {
+		return true
+	}
+
+	number, ok := errNumber(err)
+	if !ok {
+		return false
+	}
+
+	numbers := r.Numbers
+	if numbers == nil {
+		numbers = DefaultRetryPolicy().Numbers
+	}
+
+	for _, n := range numbers {
+		if n == number {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *RetryPolicy) delay(attempt int) time.Duration {
+	delay := r.BaseDelay << (attempt - 1)
+	if r.MaxDelay > 0 && (delay > r.MaxDelay || delay < r.BaseDelay) {
+		delay = r.MaxDelay
+	}
+
+	if r.Jitter > 0 && delay > 0 {
+		delay -= time.Duration(rand.Float64() * r.Jitter * float64(delay))
+	}
+
+	return delay
+}
+
+func (r *RetryPolicy) wait(ctx context.Context, attempt int) error {
+	delay := r.delay(attempt)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// run call with the policy, classify the error with check
+func (r *RetryPolicy) Do(ctx context.Context, check func(err error) bool, call func() error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = call(); err == nil || attempt >= r.MaxAttempts || !check(err) {
+			return err
+		}
+
+		if waitErr := r.wait(ctx, attempt); waitErr != nil {
+			return err
+		}
+	}
+}
+
+// retry idempotent reads, statements in transaction are never retried
+func _retry(ctx context.Context, conn ksql.ConnectionInterface, call func() error) error {
+	guarded := func() error {
+		return _guarded(ctx, conn, call)
+	}
+
+	policy := _policy(conn)
+	if policy == nil || conn.InTransaction() {
+		return guarded()
+	}
+
+	return policy.Do(ctx, policy.IsRetryable, guarded)
+}
+
+func _retryTx(ctx context.Context, conn ksql.ConnectionInterface, call func() ksql.TxError) ksql.TxError {
+	policy := _policy(conn)
+	if policy == nil {
+		return call()
+	}
+
+	var txErr ksql.TxError
+	policy.Do(ctx, func(err error) bool {
+		// commit error is ambiguous, the transaction may have been committed
+		return policy.IsRetryable(txErr.Begin()) || policy.IsRetryable(txErr.Call())
+	}, func() error {
+		txErr = call()
+		if txErr == nil {
+			return nil
+		}
+
+		return txErr
+	})
+
+	return txErr
+}
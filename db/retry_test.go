@@ -0,0 +1,170 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	ksql "github.com/kovey/db-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func testRetryPolicy() *RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+	return policy
+}
+
+func TestRetryPolicyIsRetryable(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	assert.True(t, policy.IsRetryable(&mysql.MySQLError{Number: Mysql_Err_Deadlock}))
+	assert.True(t, policy.IsRetryable(&SqlErr{Sql: "SELECT 1", Err: &mysql.MySQLError{Number: Mysql_Err_Lock_Wait_Timeout}}))
+	assert.True(t, policy.IsRetryable(&SqlErr{Sql: "SELECT 1", Err: driver.ErrBadConn}))
+	assert.True(t, policy.IsRetryable(&mysql.MySQLError{Number: Mysql_Err_Option_Prevents}))
+	assert.False(t, policy.IsRetryable(&mysql.MySQLError{Number: 1062}))
+	assert.False(t, policy.IsRetryable(errors.New("other")))
+	assert.False(t, policy.IsRetryable(nil))
+
+	policy.Numbers = []uint16{1062}
+	assert.True(t, policy.IsRetryable(&mysql.MySQLError{Number: 1062}))
+	assert.False(t, policy.IsRetryable(&mysql.MySQLError{Number: Mysql_Err_Deadlock}))
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := &RetryPolicy{BaseDelay: 10 * time.Millisecond, MaxDelay: 30 * time.Millisecond}
+	assert.Equal(t, 10*time.Millisecond, policy.delay(1))
+	assert.Equal(t, 20*time.Millisecond, policy.delay(2))
+	assert.Equal(t, 30*time.Millisecond, policy.delay(3))
+	policy.Jitter = 0.5
+	for i := 0; i < 10; i++ {
+		delay := policy.delay(2)
+		assert.True(t, delay > 10*time.Millisecond && delay <= 20*time.Millisecond)
+	}
+}
+
+func TestRetryQueryRow(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := Open(testDb, "mysql")
+	SetRetryPolicy(testRetryPolicy())
+	defer SetRetryPolicy(nil)
+
+	mock.ExpectPrepare("SELECT `id`, `age`, `name`, `create_time`, `balance` FROM `user` WHERE `id` = ?").ExpectQuery().WithArgs(1).WillReturnError(&mysql.MySQLError{Number: Mysql_Err_Deadlock})
+	mock.ExpectPrepare("SELECT `id`, `age`, `name`, `create_time`, `balance` FROM `user` WHERE `id` = ?").ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "age", "name", "create_time", "balance"}).AddRow(1, 18, "kovey", "2025-01-01 00:00:00", 1.5))
+	u := newTestUser()
+	q := NewQuery().Table("user").Columns(u.Columns()...).Where("id", ksql.Eq, 1)
+	err := QueryRowBy(context.Background(), conn, q, u)
+	assert.Nil(t, err)
+	assert.Equal(t, "kovey", u.Name)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestRetryQueryExhausted(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := Open(testDb, "mysql")
+	policy := testRetryPolicy()
+	policy.MaxAttempts = 2
+	SetRetryPolicy(policy)
+	defer SetRetryPolicy(nil)
+
+	lockErr := &mysql.MySQLError{Number: Mysql_Err_Lock_Wait_Timeout}
+	mock.ExpectPrepare("SELECT `id` FROM `user`").ExpectQuery().WillReturnError(lockErr)
+	mock.ExpectPrepare("SELECT `id` FROM `user`").ExpectQuery().WillReturnError(lockErr)
+	var users []*test_user
+	err := QueryBy(context.Background(), conn, NewQuery().Table("user").Columns("id"), &users)
+	assert.Equal(t, lockErr, err.(*SqlErr).Err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestRetryNotInTransaction(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := Open(testDb, "mysql")
+	SetRetryPolicy(testRetryPolicy())
+	defer SetRetryPolicy(nil)
+
+	deadlock := &mysql.MySQLError{Number: Mysql_Err_Deadlock}
+	mock.ExpectBegin()
+	mock.ExpectPrepare("SELECT `id` FROM `user`").ExpectQuery().WillReturnError(deadlock)
+	mock.ExpectRollback()
+	err := conn.Transaction(context.Background(), func(ctx context.Context, conn ksql.ConnectionInterface) error {
+		var users []*test_user
+		return QueryBy(ctx, conn, NewQuery().Table("user").Columns("id"), &users)
+	})
+	assert.Equal(t, deadlock, err.Call().(*SqlErr).Err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestRetryTransaction(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := Open(testDb, "mysql")
+	database = conn
+	SetRetryPolicy(testRetryPolicy())
+	defer SetRetryPolicy(nil)
+
+	mock.ExpectBegin()
+	mock.ExpectPrepare("UPDATE `user` SET `age` = ? WHERE `id` = ?").ExpectExec().WithArgs(20, 1).WillReturnError(&mysql.MySQLError{Number: Mysql_Err_Deadlock})
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectPrepare("UPDATE `user` SET `age` = ? WHERE `id` = ?").ExpectExec().WithArgs(20, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	calls := 0
+	err := Transaction(context.Background(), func(ctx context.Context, conn ksql.ConnectionInterface) error {
+		calls++
+		_, err := conn.Exec(ctx, NewUpdate().Table("user").Set("age", 20).Where(NewWhere().Where("id", ksql.Eq, 1)))
+		return err
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestRetryConnectionPolicy(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	policy := testRetryPolicy()
+	policy.MaxAttempts = 2
+	conn, err := OpenBy(testDb, Config{DriverName: "mysql", Retry: policy})
+	assert.Nil(t, err)
+	other, _ := Open(testDb, "mysql")
+	SetRetryPolicy(nil)
+
+	// the policy of the connection applies without a global one, clones keep it
+	deadlock := &mysql.MySQLError{Number: Mysql_Err_Deadlock}
+	mock.ExpectPrepare("SELECT `id` FROM `user`").ExpectQuery().WillReturnError(deadlock)
+	mock.ExpectPrepare("SELECT `id` FROM `user`").ExpectQuery().WillReturnRows(sqlmock.NewRows(newTestUser().Columns()).AddRow(1, 18, "kovey", "2025-01-01 00:00:00", 1.5))
+	var users []*test_user
+	assert.Nil(t, QueryBy(context.Background(), conn.Clone(), NewQuery().Table("user").Columns("id"), &users))
+	assert.Equal(t, 1, len(users))
+
+	mock.ExpectPrepare("SELECT `id` FROM `user`").ExpectQuery().WillReturnError(deadlock)
+	err = QueryBy(context.Background(), other, NewQuery().Table("user").Columns("id"), &users)
+	assert.Equal(t, deadlock, err.(*SqlErr).Err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestRetryInitKeepsPolicy(t *testing.T) {
+	dsn := fmt.Sprintf("retry_init_%d", time.Now().UnixNano())
+	testDb, _, err := sqlmock.NewWithDSN(dsn)
+	assert.Nil(t, err)
+	defer testDb.Close()
+	policy := testRetryPolicy()
+	SetRetryPolicy(policy)
+	defer SetRetryPolicy(nil)
+
+	// a config without Retry keeps the global policy
+	assert.Nil(t, Init(Config{DriverName: "sqlmock", DataSourceName: dsn}))
+	assert.Same(t, policy, retryPolicy.Load())
+
+	other := testRetryPolicy()
+	assert.Nil(t, Init(Config{DriverName: "sqlmock", DataSourceName: dsn, Retry: other}))
+	assert.Same(t, other, retryPolicy.Load())
+}
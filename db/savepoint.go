@@ -0,0 +1,114 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	ksql "github.com/kovey/db-go/v3"
+	"github.com/kovey/db-go/v3/db/driver"
+	ks "github.com/kovey/db-go/v3/sql"
+)
+
+var Err_Invalid_Save_Point = errors.New("invalid save point name")
+
+// savepoint names are identifiers: letters, digits, _ and $, not starting with a digit, at most 64 bytes
+func validSavePoint(point string) bool {
+	if point == "" || len(point) > 64 || (point[0] >= '0' && point[0] <= '9') {
+		return false
+	}
+
+	for i := 0; i < len(point); i++ {
+		c := point[i]
+		if c != '_' && c != '$' && (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// _savePoint runs keyword `point` on the transaction directly, savepoint statements can not be prepared
+func (c *Connection) _savePoint(ctx context.Context, keyword, point string) error {
+	if !driver.SupportSavePoint(c.driverName) {
+		return Err_Un_Support_Save_Point
+	}
+
+	if !validSavePoint(point) {
+		return Err_Invalid_Save_Point
+	}
+
+	if c.tx == nil {
+		return Err_Not_In_Transaction
+	}
+
+	var builder strings.Builder
+	builder.WriteString(keyword)
+	builder.WriteString(" ")
+	ks.Backtick(point, &builder)
+	raw := ks.Raw(builder.String())
+
+	cc := NewContext(ctx)
+	cc.RawSqlLogStart(raw)
+	defer cc.SqlLogEnd()
+
+	_, err := c.tx.ExecContext(cc, raw.Statement())
+	return _errRaw(err, raw)
+}
+
+// Savepoint runs call inside a savepoint of the current transaction, the savepoint is rolled back
+// when call fails or panics and released otherwise
+func (c *Connection) Savepoint(ctx context.Context, call func(ctx context.Context, conn ksql.ConnectionInterface) error) error {
+	if c.tx == nil {
+		return Err_Not_In_Transaction
+	}
+
+	if !driver.SupportSavePoint(c.driverName) {
+		return Err_Un_Support_Save_Point
+	}
+
+	if err := c.beginTo(ctx); err != nil {
+		return &TxErr{beginErr: err}
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			c.rollbackTo(ctx)
+			panic(r)
+		}
+	}()
+
+	if callErr := call(WithTx(ctx, c), c); callErr != nil {
+		txErr := &TxErr{callErr: callErr}
+		if err := c.rollbackTo(ctx); err != nil {
+			txErr.rollbackErr = err
+		}
+
+		return txErr
+	}
+
+	if err := c.commitTo(ctx); err != nil {
+		return &TxErr{commitErr: err}
+	}
+
+	return nil
+}
+
+type savepointConnection interface {
+	Savepoint(ctx context.Context, call func(ctx context.Context, conn ksql.ConnectionInterface) error) error
+}
+
+// Savepoint runs call inside a savepoint of the transaction of ctx
+func Savepoint(ctx context.Context, call func(ctx context.Context, conn ksql.ConnectionInterface) error) error {
+	tx, ok := TxFrom(ctx)
+	if !ok {
+		return Err_Not_In_Transaction
+	}
+
+	c, ok := tx.(savepointConnection)
+	if !ok {
+		return Err_Un_Support_Save_Point
+	}
+
+	return c.Savepoint(ctx, call)
+}
@@ -0,0 +1,88 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	ksql "github.com/kovey/db-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidSavePoint(t *testing.T) {
+	assert.True(t, validSavePoint("trans_1"))
+	assert.True(t, validSavePoint("_a$b"))
+	assert.False(t, validSavePoint(""))
+	assert.False(t, validSavePoint("1a"))
+	assert.False(t, validSavePoint("a`; DROP TABLE user"))
+	assert.False(t, validSavePoint("a b"))
+	assert.False(t, validSavePoint(string(make([]byte, 65))))
+}
+
+func TestSavePointStatements(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := Open(testDb, "mysql")
+
+	assert.Equal(t, Err_Not_In_Transaction, conn.BeginTo(context.Background(), "a"))
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT `a`").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("ROLLBACK TO SAVEPOINT `a`").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("RELEASE SAVEPOINT `a`").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	assert.Nil(t, conn.Begin(context.Background(), nil))
+	assert.Equal(t, Err_Invalid_Save_Point, conn.BeginTo(context.Background(), "a`b"))
+	assert.Nil(t, conn.BeginTo(context.Background(), "a"))
+	assert.Nil(t, conn.RollbackTo(context.Background(), "a"))
+	assert.Nil(t, conn.CommitTo(context.Background(), "a"))
+	assert.Nil(t, conn.Commit(context.Background()))
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestSavepointScope(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := Open(testDb, "mysql")
+	database = conn
+
+	var events []string
+	failed := errors.New("failed")
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT `trans_1`").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectPrepare("DELETE FROM `user` WHERE `id` = ?").ExpectExec().WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("ROLLBACK TO SAVEPOINT `trans_1`").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("SAVEPOINT `trans_1`").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("RELEASE SAVEPOINT `trans_1`").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("SAVEPOINT `trans_1`").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("ROLLBACK TO SAVEPOINT `trans_1`").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	assert.Equal(t, Err_Not_In_Transaction, Savepoint(context.Background(), func(ctx context.Context, conn ksql.ConnectionInterface) error { return nil }))
+	err := Transaction(context.Background(), func(ctx context.Context, tx ksql.ConnectionInterface) error {
+		err := Savepoint(ctx, func(ctx context.Context, conn ksql.ConnectionInterface) error {
+			OnCommit(ctx, func() { events = append(events, "dropped") })
+			OnRollback(ctx, func() { events = append(events, "savepoint rollback") })
+			if _, err := Delete(ctx, "user", NewWhere().Where("id", ksql.Eq, 1)); err != nil {
+				return err
+			}
+			return failed
+		})
+		assert.Equal(t, failed, err.(ksql.TxError).Call())
+
+		txErr := TransactionWith(ctx, Propagation_Nested, nil, func(ctx context.Context, conn ksql.ConnectionInterface) error {
+			OnCommit(ctx, func() { events = append(events, "nested commit") })
+			return nil
+		})
+		assert.Nil(t, txErr)
+
+		assert.Panics(t, func() {
+			Savepoint(ctx, func(ctx context.Context, conn ksql.ConnectionInterface) error { panic("boom") })
+		})
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"savepoint rollback", "nested commit"}, events)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,161 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+
+	ksql "github.com/kovey/db-go/v3"
+)
+
+var rawBytesType = reflect.TypeOf(sql.RawBytes{})
+
+// _rows runs op on conn and calls each for every row, the whole read is retried on transient errors
+func _rows(ctx context.Context, conn ksql.ConnectionInterface, op ksql.QueryInterface, reset func(), each func(rows *sql.Rows) error) error {
+	return _retry(ctx, conn, func() error {
+		reset()
+		return _rowsOnce(ctx, conn, op, each)
+	})
+}
+
+func _rowsOnce(ctx context.Context, conn ksql.ConnectionInterface, op ksql.QueryInterface, each func(rows *sql.Rows) error) error {
+	cc := NewContext(ctx)
+	cc.SqlLogStart(op)
+	defer cc.SqlLogEnd()
+
+	stmt, err := _prepare(cc, conn, op)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(cc, op.Binds()...)
+	if err != nil {
+		return _err(err, op)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := each(rows); err != nil {
+			return _err(err, op)
+		}
+	}
+
+	return _err(rows.Err(), op)
+}
+
+// PluckBy selects column of every row matched by query
+func PluckBy[T any](ctx context.Context, conn ksql.ConnectionInterface, query ksql.QueryInterface, column string) ([]T, error) {
+	q := query.Clone()
+	q.Column(column, "")
+	var values []T
+	err := _rows(ctx, conn, q, func() { values = values[:0] }, func(rows *sql.Rows) error {
+		var value T
+		if err := rows.Scan(&value); err != nil {
+			return err
+		}
+
+		values = append(values, value)
+		return nil
+	})
+	return values, err
+}
+
+// Pluck selects column of every row matched by query, use a pointer or sql.Null type as T for nullable columns
+func Pluck[T any](ctx context.Context, query ksql.QueryInterface, column string) ([]T, error) {
+	return PluckBy[T](ctx, _database(ctx), query, column)
+}
+
+// ScanPairsBy selects key and value column of every row matched by query into a map, later rows win on duplicate keys
+func ScanPairsBy[K comparable, V any](ctx context.Context, conn ksql.ConnectionInterface, query ksql.QueryInterface, key, value string) (map[K]V, error) {
+	q := query.Clone()
+	q.Column(key, "").Column(value, "")
+	pairs := make(map[K]V)
+	err := _rows(ctx, conn, q, func() { clear(pairs) }, func(rows *sql.Rows) error {
+		var k K
+		var v V
+		if err := rows.Scan(&k, &v); err != nil {
+			return err
+		}
+
+		pairs[k] = v
+		return nil
+	})
+	return pairs, err
+}
+
+func ScanPairs[K comparable, V any](ctx context.Context, query ksql.QueryInterface, key, value string) (map[K]V, error) {
+	return ScanPairsBy[K, V](ctx, _database(ctx), query, key, value)
+}
+
+// ScanMapBy reads every row matched by query into a map of column name to value,
+// values have the go type the driver reports for the column, NULL is nil and text is string
+func ScanMapBy(ctx context.Context, conn ksql.ConnectionInterface, query ksql.QueryInterface) ([]map[string]any, error) {
+	var result []map[string]any
+	var columns []string
+	var types []*sql.ColumnType
+	err := _rows(ctx, conn, query, func() { result, columns, types = result[:0], nil, nil }, func(rows *sql.Rows) error {
+		if columns == nil {
+			var err error
+			if columns, err = rows.Columns(); err != nil {
+				return err
+			}
+			if types, err = rows.ColumnTypes(); err != nil {
+				return err
+			}
+		}
+
+		dests := make([]any, len(columns))
+		for index, typ := range types {
+			dests[index] = _scanDest(typ)
+		}
+
+		if err := rows.Scan(dests...); err != nil {
+			return err
+		}
+
+		row := make(map[string]any, len(columns))
+		for index, column := range columns {
+			row[column] = _scanValue(dests[index])
+		}
+
+		result = append(result, row)
+		return nil
+	})
+	return result, err
+}
+
+func ScanMap(ctx context.Context, query ksql.QueryInterface) ([]map[string]any, error) {
+	return ScanMapBy(ctx, _database(ctx), query)
+}
+
+func _scanDest(typ *sql.ColumnType) any {
+	scanType := typ.ScanType()
+	if scanType == nil || scanType.Kind() == reflect.Interface || scanType == rawBytesType {
+		return new(any)
+	}
+
+	return reflect.New(scanType).Interface()
+}
+
+func _scanValue(dest any) any {
+	switch value := dest.(type) {
+	case *any:
+		if b, ok := (*value).([]byte); ok {
+			return string(b)
+		}
+
+		return *value
+	case driver.Valuer:
+		// sql.NullInt64, sql.NullString, sql.NullTime...
+		v, err := value.Value()
+		if err != nil {
+			return nil
+		}
+
+		return v
+	}
+
+	return reflect.ValueOf(dest).Elem().Interface()
+}
@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	ksql "github.com/kovey/db-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPluck(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := Open(testDb, "mysql")
+	database = conn
+
+	mock.ExpectPrepare("SELECT `name` FROM `user` WHERE `age` > ?").ExpectQuery().WithArgs(18).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("kovey").AddRow("alice"))
+	mock.ExpectPrepare("SELECT `nickname` FROM `user` WHERE `age` > ?").ExpectQuery().WithArgs(18).WillReturnRows(sqlmock.NewRows([]string{"nickname"}).AddRow("k").AddRow(nil))
+
+	query := NewQuery().Table("user").Columns("id").Where("age", ksql.Gt, 18)
+	names, err := Pluck[string](context.Background(), query, "name")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"kovey", "alice"}, names)
+
+	nicknames, err := Pluck[sql.NullString](context.Background(), query, "nickname")
+	assert.Nil(t, err)
+	assert.Equal(t, []sql.NullString{{String: "k", Valid: true}, {}}, nicknames)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestScanPairs(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := Open(testDb, "mysql")
+	database = conn
+
+	mock.ExpectPrepare("SELECT `id`, `name` FROM `user` WHERE `id` IN (?, ?)").ExpectQuery().WithArgs(1, 2).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "kovey").AddRow(2, "alice"))
+
+	pairs, err := ScanPairs[int64, string](context.Background(), NewQuery().Table("user").WhereIn("id", []any{1, 2}), "id", "name")
+	assert.Nil(t, err)
+	assert.Equal(t, map[int64]string{1: "kovey", 2: "alice"}, pairs)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestScanMap(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := Open(testDb, "mysql")
+	database = conn
+
+	rows := sqlmock.NewRowsWithColumnDefinition(
+		sqlmock.NewColumn("id").OfType("BIGINT", int64(0)),
+		sqlmock.NewColumn("name").OfType("VARCHAR", sql.RawBytes{}),
+		sqlmock.NewColumn("score").OfType("DOUBLE", sql.NullFloat64{}).Nullable(true),
+		sqlmock.NewColumn("remark").OfType("TEXT", nil),
+	).AddRow(int64(1), []byte("kovey"), 9.5, []byte("vip")).AddRow(int64(2), []byte("alice"), nil, nil)
+	mock.ExpectPrepare("SELECT `id`, `name`, `score`, `remark` FROM `user`").ExpectQuery().WillReturnRows(rows)
+
+	result, err := ScanMap(context.Background(), NewQuery().Table("user").Columns("id", "name", "score", "remark"))
+	assert.Nil(t, err)
+	assert.Equal(t, []map[string]any{
+		{"id": int64(1), "name": "kovey", "score": 9.5, "remark": "vip"},
+		{"id": int64(2), "name": "alice", "score": nil, "remark": nil},
+	}, result)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,275 @@
+package db
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"sync"
+
+	ksql "github.com/kovey/db-go/v3"
+)
+
+type StmtCacheConfig struct {
+	Size    int                // max cached statements, default 256
+	OnEvict func(query string) // called when a statement is evicted
+}
+
+type StmtCacheStats struct {
+	Size      int
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+}
+
+// statement runs a prepared or interpolated sql, Close releases it
+type statement interface {
+	ExecContext(ctx context.Context, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, args ...any) *sql.Row
+	Close() error
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// interpolated runs the sql on the db or tx directly, the driver interpolates or prepares it
+type interpolated struct {
+	conn  execer
+	query string
+}
+
+func (i *interpolated) ExecContext(ctx context.Context, args ...any) (sql.Result, error) {
+	return i.conn.ExecContext(ctx, i.query, args...)
+}
+
+func (i *interpolated) QueryContext(ctx context.Context, args ...any) (*sql.Rows, error) {
+	return i.conn.QueryContext(ctx, i.query, args...)
+}
+
+func (i *interpolated) QueryRowContext(ctx context.Context, args ...any) *sql.Row {
+	return i.conn.QueryRowContext(ctx, i.query, args...)
+}
+
+func (i *interpolated) Close() error {
+	return nil
+}
+
+type stmtEntry struct {
+	query   string
+	stmt    *sql.Stmt
+	refs    int
+	evicted bool
+}
+
+// cached is a borrowed statement of the cache, inside a transaction stmt is the tx bound copy
+type cached struct {
+	*sql.Stmt
+	cache *stmtCache
+	entry *stmtEntry
+	tx    bool
+}
+
+func (c *cached) Close() error {
+	var err error
+	if c.tx {
+		err = c.Stmt.Close()
+	}
+
+	c.cache.release(c.entry)
+	return err
+}
+
+// lru cache of prepared statements of one *sql.DB, keyed by sql text
+type stmtCache struct {
+	mu        sync.Mutex
+	size      int
+	items     map[string]*list.Element
+	lru       *list.List
+	onEvict   func(query string)
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+func newStmtCache(conf *StmtCacheConfig) *stmtCache {
+	if conf == nil {
+		return nil
+	}
+
+	size := conf.Size
+	if size <= 0 {
+		size = 256
+	}
+
+	return &stmtCache{size: size, items: make(map[string]*list.Element), lru: list.New(), onEvict: conf.OnEvict}
+}
+
+func (s *stmtCache) acquire(query string) *stmtEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	el, ok := s.items[query]
+	if !ok {
+		s.misses++
+		return nil
+	}
+
+	s.hits++
+	s.lru.MoveToFront(el)
+	entry := el.Value.(*stmtEntry)
+	entry.refs++
+	return entry
+}
+
+// add caches stmt, an entry cached meanwhile by another caller wins and stmt is closed
+func (s *stmtCache) add(query string, stmt *sql.Stmt) *stmtEntry {
+	s.mu.Lock()
+	if el, ok := s.items[query]; ok {
+		entry := el.Value.(*stmtEntry)
+		entry.refs++
+		s.lru.MoveToFront(el)
+		s.mu.Unlock()
+		stmt.Close()
+		return entry
+	}
+
+	entry := &stmtEntry{query: query, stmt: stmt, refs: 1}
+	s.items[query] = s.lru.PushFront(entry)
+	var evicted []*stmtEntry
+	for s.lru.Len() > s.size {
+		el := s.lru.Back()
+		old := el.Value.(*stmtEntry)
+		s.lru.Remove(el)
+		delete(s.items, old.query)
+		old.evicted = true
+		s.evictions++
+		evicted = append(evicted, old)
+	}
+	s.mu.Unlock()
+
+	for _, old := range evicted {
+		s.evict(old)
+	}
+
+	return entry
+}
+
+func (s *stmtCache) release(entry *stmtEntry) {
+	s.mu.Lock()
+	entry.refs--
+	closed := entry.evicted && entry.refs == 0
+	s.mu.Unlock()
+	if closed {
+		entry.stmt.Close()
+	}
+}
+
+func (s *stmtCache) evict(entry *stmtEntry) {
+	if s.onEvict != nil {
+		s.onEvict(entry.query)
+	}
+
+	s.mu.Lock()
+	closed := entry.refs == 0
+	s.mu.Unlock()
+	if closed {
+		entry.stmt.Close()
+	}
+}
+
+func (s *stmtCache) stats() StmtCacheStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return StmtCacheStats{Size: s.lru.Len(), Hits: s.hits, Misses: s.misses, Evictions: s.evictions}
+}
+
+func (c *Connection) _statement(ctx context.Context, query string) (statement, error) {
+	if c.interpolate {
+		if c.tx != nil {
+			return &interpolated{conn: c.tx, query: query}, nil
+		}
+
+		return &interpolated{conn: c.database, query: query}, nil
+	}
+
+	if c.stmts == nil {
+		var stmt *sql.Stmt
+		var err error
+		if c.tx != nil {
+			stmt, err = c.tx.PrepareContext(ctx, query)
+		} else {
+			stmt, err = c.database.PrepareContext(ctx, query)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return stmt, nil
+	}
+
+	entry := c.stmts.acquire(query)
+	if entry == nil {
+		stmt, err := c.database.PrepareContext(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+
+		entry = c.stmts.add(query, stmt)
+	}
+
+	if c.tx == nil {
+		return &cached{Stmt: entry.stmt, cache: c.stmts, entry: entry}, nil
+	}
+
+	return &cached{Stmt: c.tx.StmtContext(ctx, entry.stmt), cache: c.stmts, entry: entry, tx: true}, nil
+}
+
+type stmtConnection interface {
+	_statement(ctx context.Context, query string) (statement, error)
+}
+
+// _prepare uses the statement cache or interpolation of conn when it supports them
+func _prepare(ctx context.Context, conn ksql.ConnectionInterface, op ksql.SqlInterface) (statement, error) {
+	if c, ok := conn.(stmtConnection); ok {
+		stmt, err := c._statement(ctx, op.Prepare())
+		return stmt, _err(err, op)
+	}
+
+	stmt, err := conn.Prepare(ctx, op)
+	if err != nil {
+		return nil, err
+	}
+
+	return stmt, nil
+}
+
+func _prepareRaw(ctx context.Context, conn ksql.ConnectionInterface, raw ksql.ExpressInterface) (statement, error) {
+	// a prepared statement holds exactly one statement
+	if m, ok := raw.(multiExpress); ok && m.IsMulti() {
+		return nil, _errRaw(Err_Sql_Multi, raw)
+	}
+
+	if c, ok := conn.(stmtConnection); ok {
+		stmt, err := c._statement(ctx, raw.Statement())
+		return stmt, _errRaw(err, raw)
+	}
+
+	stmt, err := conn.PrepareRaw(ctx, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return stmt, nil
+}
+
+// statistics of the statement cache of conn, zero when conn has no cache
+func StmtCacheStatsOf(conn ksql.ConnectionInterface) StmtCacheStats {
+	c, ok := conn.(*Connection)
+	if !ok || c.stmts == nil {
+		return StmtCacheStats{}
+	}
+
+	return c.stmts.stats()
+}
@@ -0,0 +1,96 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	ksql "github.com/kovey/db-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStmtCache(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	var evicted []string
+	conn, err := OpenBy(testDb, Config{DriverName: "mysql", StmtCache: &StmtCacheConfig{Size: 1, OnEvict: func(query string) { evicted = append(evicted, query) }}})
+	assert.Nil(t, err)
+
+	prepare := mock.ExpectPrepare("UPDATE `user` SET `age` = ? WHERE `id` = ?").WillBeClosed()
+	prepare.ExpectExec().WithArgs(18, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	prepare.ExpectExec().WithArgs(19, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	_, err = conn.Exec(context.Background(), NewUpdate().Table("user").Set("age", 18).Where(NewWhere().Where("id", ksql.Eq, 1)))
+	assert.Nil(t, err)
+	_, err = conn.Clone().Exec(context.Background(), NewUpdate().Table("user").Set("age", 19).Where(NewWhere().Where("id", ksql.Eq, 2)))
+	assert.Nil(t, err)
+	assert.Equal(t, StmtCacheStats{Size: 1, Hits: 1, Misses: 1}, StmtCacheStatsOf(conn))
+
+	mock.ExpectPrepare("SELECT `name` FROM `user`").ExpectQuery().WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("kovey"))
+	var name string
+	assert.Nil(t, conn.ScanRaw(context.Background(), Raw("SELECT `name` FROM `user`"), &name))
+	assert.Equal(t, "kovey", name)
+	assert.Equal(t, StmtCacheStats{Size: 1, Hits: 1, Misses: 2, Evictions: 1}, StmtCacheStatsOf(conn))
+	assert.Equal(t, []string{"UPDATE `user` SET `age` = ? WHERE `id` = ?"}, evicted)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestStmtCacheTransaction(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := OpenBy(testDb, Config{DriverName: "mysql", StmtCache: &StmtCacheConfig{}})
+
+	prepare := mock.ExpectPrepare("DELETE FROM `user` WHERE `id` = ?")
+	prepare.ExpectExec().WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectBegin()
+	prepare.ExpectExec().WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	_, err := conn.Exec(context.Background(), NewDelete().Table("user").Where(NewWhere().Where("id", ksql.Eq, 1)))
+	assert.Nil(t, err)
+	txErr := conn.Transaction(context.Background(), func(ctx context.Context, tx ksql.ConnectionInterface) error {
+		_, err := tx.Exec(ctx, NewDelete().Table("user").Where(NewWhere().Where("id", ksql.Eq, 2)))
+		return err
+	})
+	assert.Nil(t, txErr)
+	assert.Equal(t, StmtCacheStats{Size: 1, Hits: 1, Misses: 1}, StmtCacheStatsOf(conn))
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestStmtCacheEvictInUse(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	mock.ExpectPrepare("SELECT 1").WillBeClosed()
+	mock.ExpectPrepare("SELECT 2")
+	c := newStmtCache(&StmtCacheConfig{Size: 1})
+
+	stmt, _ := testDb.Prepare("SELECT 1")
+	first := c.add("SELECT 1", stmt)
+	stmt, _ = testDb.Prepare("SELECT 2")
+	second := c.add("SELECT 2", stmt)
+	assert.True(t, first.evicted)
+	assert.Equal(t, 1, first.refs)
+	assert.NotNil(t, mock.ExpectationsWereMet())
+
+	c.release(first)
+	c.release(second)
+	assert.Nil(t, mock.ExpectationsWereMet())
+	assert.Nil(t, c.acquire("SELECT 1"))
+	assert.Equal(t, second, c.acquire("SELECT 2"))
+}
+
+func TestInterpolate(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := OpenBy(testDb, Config{DriverName: "mysql", Interpolate: true})
+
+	mock.ExpectExec("INSERT INTO `user` (`name`) VALUES (?)").WithArgs("kovey").WillReturnResult(sqlmock.NewResult(3, 1))
+	mock.ExpectQuery("SELECT COUNT(1) FROM `user`").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	id, err := conn.Exec(context.Background(), NewInsert().Table("user").Add("name", "kovey"))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), id)
+	var count int
+	assert.Nil(t, conn.ScanRaw(context.Background(), Raw("SELECT COUNT(1) FROM `user`"), &count))
+	assert.Equal(t, 3, count)
+	assert.Equal(t, StmtCacheStats{}, StmtCacheStatsOf(conn))
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+
+	ksql "github.com/kovey/db-go/v3"
+)
+
+type strictKey struct{}
+
+// Strict makes the single row reads with ctx return Err_Not_Found when no row matches,
+// by default they return nil and leave the destination untouched
+func Strict(ctx context.Context) context.Context {
+	return context.WithValue(ctx, strictKey{}, true)
+}
+
+// IsStrict reports whether ctx was made by Strict
+func IsStrict(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+
+	strict, _ := ctx.Value(strictKey{}).(bool)
+	return strict
+}
+
+// _noRows is the error of a single row read that matched no row
+func _noRows(ctx context.Context) error {
+	if IsStrict(ctx) {
+		return Err_Not_Found
+	}
+
+	return nil
+}
+
+// MustScan is Scan returning Err_Not_Found when no row matches
+func MustScan(ctx context.Context, query ksql.QueryInterface, vals ...any) error {
+	return Scan(Strict(ctx), query, vals...)
+}
+
+func MustScanBy(ctx context.Context, conn ksql.ConnectionInterface, query ksql.QueryInterface, vals ...any) error {
+	return ScanBy(Strict(ctx), conn, query, vals...)
+}
+
+// MustScanRaw is ScanRaw of the connection returning Err_Not_Found when no row matches
+func MustScanRaw(ctx context.Context, raw ksql.ExpressInterface, vals ...any) error {
+	return _database(ctx).ScanRaw(Strict(ctx), raw, vals...)
+}
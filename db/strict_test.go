@@ -0,0 +1,63 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	ksql "github.com/kovey/db-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanNoRows(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := Open(testDb, "mysql")
+	database = conn
+
+	for i := 0; i < 2; i++ {
+		mock.ExpectPrepare("SELECT `name` FROM `user` WHERE `id` = ?").ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"name"}))
+	}
+	mock.ExpectPrepare("SELECT `name` FROM `user` WHERE `id` = ?").ExpectQuery().WithArgs(1).WillReturnError(errors.New("connection lost"))
+	mock.ExpectPrepare("SELECT name FROM user WHERE id = ?").ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"name"}))
+
+	var name string
+	query := NewQuery().Table("user").Columns("name").Where("id", ksql.Eq, 1)
+	assert.Nil(t, Scan(context.Background(), query, &name))
+
+	err := MustScan(context.Background(), query, &name)
+	assert.True(t, errors.Is(err, Err_Not_Found))
+	assert.True(t, errors.Is(err, sql.ErrNoRows))
+	assert.Equal(t, Err_No_Rows, Classify(err))
+
+	err = Scan(context.Background(), query, &name)
+	assert.NotNil(t, err)
+	assert.False(t, errors.Is(err, Err_Not_Found))
+
+	err = MustScanRaw(context.Background(), Raw("SELECT name FROM user WHERE id = ?", 1), &name)
+	assert.True(t, errors.Is(err, Err_Not_Found))
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestFirstOrErr(t *testing.T) {
+	testDb, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer testDb.Close()
+	conn, _ := Open(testDb, "mysql")
+	database = conn
+
+	mock.ExpectPrepare("SELECT `id`, `name` FROM `user` WHERE `id` = ?").ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	mock.ExpectPrepare("SELECT `id`, `name` FROM `user` WHERE `id` = ?").ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "kovey"))
+
+	user := &propagation_user{}
+	err := Build(user).Table("user").Columns("id", "name").Where("id", ksql.Eq, 1).FirstOrErr(context.Background())
+	assert.True(t, errors.Is(err, Err_Not_Found))
+
+	user = &propagation_user{}
+	assert.Nil(t, Build(user).Table("user").Columns("id", "name").Where("id", ksql.Eq, 1).FirstOrErr(context.Background()))
+	assert.Equal(t, "kovey", user.Name)
+	assert.True(t, IsStrict(Strict(context.Background())))
+	assert.False(t, IsStrict(context.Background()))
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -1,8 +1,6 @@
 package express
 
 import (
-	"strings"
-
 	ksql "github.com/kovey/db-go/v3"
 )
 
@@ -10,6 +8,7 @@ type Statement struct {
 	raw   string
 	binds []any
 	typ   ksql.SqlType
+	multi bool
 }
 
 func NewStatement(raw string, binds []any) *Statement {
@@ -19,63 +18,7 @@ func NewStatement(raw string, binds []any) *Statement {
 }
 
 func (s *Statement) parse() {
-	count := len(s.raw)
-	if count < 4 {
-		return
-	}
-	first := ksql.SqlType(strings.ToUpper(s.raw[:4]))
-	if first == ksql.Sql_Type_Drop {
-		s.typ = ksql.Sql_Type_Drop
-		return
-	}
-
-	if count < 5 {
-		return
-	}
-	first = ksql.SqlType(strings.ToUpper(s.raw[:5]))
-	switch first {
-	case ksql.Sql_Type_Alter, ksql.Sql_Type_Query:
-		s.typ = first
-		return
-	}
-
-	if count < 6 {
-		return
-	}
-
-	first = ksql.SqlType(strings.ToUpper(s.raw[:6]))
-	switch first {
-	case ksql.Sql_Type_Insert, ksql.Sql_Type_Update, ksql.Sql_Type_Delete, ksql.Sql_Type_Create:
-		s.typ = first
-	}
-
-	if count < 7 {
-		return
-	}
-	first = ksql.SqlType(strings.ToUpper(s.raw[:7]))
-	switch first {
-	case ksql.Sql_Type_Release:
-		s.typ = first
-	}
-
-	if count < 8 {
-		return
-	}
-	first = ksql.SqlType(strings.ToUpper(s.raw[:8]))
-	switch first {
-	case ksql.Sql_Type_Rollback:
-		s.typ = first
-	}
-
-	if count < 9 {
-		return
-	}
-
-	first = ksql.SqlType(strings.ToUpper(s.raw[:9]))
-	switch first {
-	case ksql.Sql_Type_Save_Point:
-		s.typ = first
-	}
+	s.typ, s.multi = classify(s.raw)
 }
 
 func (s *Statement) Statement() string {
@@ -87,7 +30,17 @@ func (s *Statement) Binds() []any {
 }
 
 func (s *Statement) IsExec() bool {
-	return s.typ != ksql.Sql_Type_Query
+	switch s.typ {
+	case ksql.Sql_Type_Query, ksql.Sql_Type_Show, ksql.Sql_Type_Explain:
+		return false
+	}
+
+	return true
+}
+
+// IsMulti reports whether the raw sql holds more than one statement
+func (s *Statement) IsMulti() bool {
+	return s.multi
 }
 
 func (s *Statement) Type() ksql.SqlType {
@@ -41,7 +41,7 @@ func TestDeteleExpress(t *testing.T) {
 
 func TestShowExpress(t *testing.T) {
 	s := NewStatement("SHOW CREATE TABLE user", nil)
-	assert.Equal(t, ksql.Sql_Type_Query, s.Type())
+	assert.Equal(t, ksql.Sql_Type_Show, s.Type())
 	assert.Equal(t, "SHOW CREATE TABLE user", s.Statement())
 	assert.Nil(t, s.Binds())
 	assert.False(t, s.IsExec())
@@ -70,3 +70,63 @@ func TestAlterTableExpress(t *testing.T) {
 	assert.Nil(t, s.Binds())
 	assert.True(t, s.IsExec())
 }
+
+func TestClassifyExpress(t *testing.T) {
+	cases := []struct {
+		raw  string
+		typ  ksql.SqlType
+		exec bool
+	}{
+		{"  \n select * from user", ksql.Sql_Type_Query, false},
+		{"/* trace */ -- note\n# hash\nSELECT 1", ksql.Sql_Type_Query, false},
+		{"(SELECT id FROM a) UNION (SELECT id FROM b)", ksql.Sql_Type_Query, false},
+		{"WITH t AS (SELECT id FROM a), u AS (DELETE FROM x) SELECT * FROM t", ksql.Sql_Type_Query, false},
+		{"with recursive t (n) as (select 1 union all select n + 1 from t) update user set n = 1", ksql.Sql_Type_Update, true},
+		{"WITH t AS (SELECT 1) DELETE FROM user", ksql.Sql_Type_Delete, true},
+		{"replace into user (id) values (1)", ksql.Sql_Type_Replace, true},
+		{"SET SESSION transaction_isolation = 'READ-COMMITTED'", ksql.Sql_Type_Set, true},
+		{"CALL proc(?)", ksql.Sql_Type_Call, true},
+		{"show tables", ksql.Sql_Type_Show, false},
+		{"EXPLAIN SELECT 1", ksql.Sql_Type_Explain, false},
+		{"desc user", ksql.Sql_Type_Explain, false},
+		{"TRUNCATE TABLE user", ksql.Sql_Type_Truncate, true},
+		{"rollback to savepoint `a`", ksql.Sql_Type_Rollback, true},
+		{"savepoint `a`", ksql.Sql_Type_Save_Point, true},
+		{"release savepoint `a`", ksql.Sql_Type_Release, true},
+		{"drop table user", ksql.Sql_Type_Drop, true},
+		{"alter table user add x int", ksql.Sql_Type_Alter, true},
+		{"START TRANSACTION", ksql.Sql_Type_Transaction, true},
+		{"GRANT SELECT ON db.* TO 'u'@'%'", ksql.Sql_Type_Grant, true},
+		{"revoke select on db.* from 'u'@'%'", ksql.Sql_Type_Revoke, true},
+		{"FLUSH PRIVILEGES", ksql.Sql_Type_Flush, true},
+		{"KILL QUERY 42", ksql.Sql_Type_Kill, true},
+		{"LOAD DATA LOCAL INFILE 'a.csv' INTO TABLE user", ksql.Sql_Type_Load, true},
+		{"ANALYZE TABLE user", ksql.Sql_Type_Maintenance, true},
+		{"optimize table user", ksql.Sql_Type_Maintenance, true},
+		{"CHECK TABLE user", ksql.Sql_Type_Maintenance, true},
+		{"CHECKSUM TABLE user", ksql.Sql_Type_Maintenance, true},
+		{"REPAIR TABLE user", ksql.Sql_Type_Maintenance, true},
+		{"HANDLER user READ FIRST", ksql.Sql_Type_Handler, true},
+		{"XA START 'x'", ksql.Sql_Type_Xa, true},
+		{"RENAME TABLE a TO b", ksql.Sql_Type_Rename, true},
+		{"TRUNCATE user", ksql.Sql_Type_Truncate, true},
+		{"help 'select'", ksql.Sql_Type_Show, false},
+		{"INSTALL PLUGIN p SONAME 'p.so'", ksql.Sql_Type_Unknown, true},
+		{"/* hint */ PURGE BINARY LOGS TO 'mysql-bin.010'", ksql.Sql_Type_Unknown, true},
+		{"", ksql.Sql_Type_Query, false},
+	}
+
+	for _, c := range cases {
+		s := NewStatement(c.raw, nil)
+		assert.Equal(t, c.typ, s.Type(), c.raw)
+		assert.Equal(t, c.exec, s.IsExec(), c.raw)
+		assert.False(t, s.IsMulti(), c.raw)
+	}
+}
+
+func TestMultiExpress(t *testing.T) {
+	assert.False(t, NewStatement("SELECT ';' FROM user; -- done\n ;", nil).IsMulti())
+	assert.False(t, NewStatement("SELECT 1 /* ; DROP TABLE user */", nil).IsMulti())
+	assert.True(t, NewStatement("SELECT 1; DROP TABLE user", nil).IsMulti())
+	assert.True(t, NewStatement("update user set a = ';'; select 1", nil).IsMulti())
+}
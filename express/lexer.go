@@ -0,0 +1,169 @@
+package express
+
+import (
+	"strings"
+
+	ksql "github.com/kovey/db-go/v3"
+)
+
+// lexer walks the keywords of a statement, skipping whitespace, comments, quoted strings and identifiers
+type lexer struct {
+	raw   string
+	pos   int
+	depth int
+	semi  bool
+}
+
+func newLexer(raw string) *lexer {
+	return &lexer{raw: raw}
+}
+
+// next returns the next upper case keyword and the paren depth it was found at, empty at the end of a statement
+func (l *lexer) next() (string, int) {
+	count := len(l.raw)
+	for l.pos < count {
+		c := l.raw[l.pos]
+		switch {
+		case isSpace(c):
+			l.pos++
+		case c == '\'' || c == '"' || c == '`':
+			l.pos = skipQuoted(l.raw, l.pos)
+		case isComment(l.raw, l.pos):
+			l.pos = skipComment(l.raw, l.pos)
+		case c == '(':
+			l.depth++
+			l.pos++
+		case c == ')':
+			l.depth--
+			l.pos++
+		case c == ';':
+			l.semi = true
+			l.pos++
+			return "", l.depth
+		case isIdentStart(c):
+			start := l.pos
+			for l.pos < count && (isIdent(l.raw[l.pos]) || l.raw[l.pos] == '$') {
+				l.pos++
+			}
+			return strings.ToUpper(l.raw[start:l.pos]), l.depth
+		default:
+			l.pos++
+		}
+	}
+
+	return "", l.depth
+}
+
+// rest reports whether anything but whitespace, comments and semicolons follows
+func (l *lexer) rest() bool {
+	for l.pos < len(l.raw) {
+		switch c := l.raw[l.pos]; {
+		case isSpace(c) || c == ';':
+			l.pos++
+		case isComment(l.raw, l.pos):
+			l.pos = skipComment(l.raw, l.pos)
+		default:
+			return true
+		}
+	}
+
+	return false
+}
+
+func isComment(raw string, pos int) bool {
+	switch raw[pos] {
+	case '#':
+		return true
+	case '-':
+		return pos+1 < len(raw) && raw[pos+1] == '-' && (pos+2 == len(raw) || isSpace(raw[pos+2]))
+	case '/':
+		return pos+1 < len(raw) && raw[pos+1] == '*'
+	}
+
+	return false
+}
+
+var keywords = map[string]ksql.SqlType{
+	"SELECT":    ksql.Sql_Type_Query,
+	"TABLE":     ksql.Sql_Type_Query,
+	"VALUES":    ksql.Sql_Type_Query,
+	"INSERT":    ksql.Sql_Type_Insert,
+	"UPDATE":    ksql.Sql_Type_Update,
+	"DELETE":    ksql.Sql_Type_Delete,
+	"REPLACE":   ksql.Sql_Type_Replace,
+	"DROP":      ksql.Sql_Type_Drop,
+	"ALTER":     ksql.Sql_Type_Alter,
+	"CREATE":    ksql.Sql_Type_Create,
+	"TRUNCATE":  ksql.Sql_Type_Truncate,
+	"RENAME":    ksql.Sql_Type_Rename,
+	"SAVEPOINT": ksql.Sql_Type_Save_Point,
+	"RELEASE":   ksql.Sql_Type_Release,
+	"ROLLBACK":  ksql.Sql_Type_Rollback,
+	"SET":       ksql.Sql_Type_Set,
+	"CALL":      ksql.Sql_Type_Call,
+	"DO":        ksql.Sql_Type_Do,
+	"SHOW":      ksql.Sql_Type_Show,
+	"EXPLAIN":   ksql.Sql_Type_Explain,
+	"DESCRIBE":  ksql.Sql_Type_Explain,
+	"DESC":      ksql.Sql_Type_Explain,
+	"BEGIN":     ksql.Sql_Type_Transaction,
+	"START":     ksql.Sql_Type_Transaction,
+	"COMMIT":    ksql.Sql_Type_Transaction,
+	"LOCK":      ksql.Sql_Type_Lock,
+	"UNLOCK":    ksql.Sql_Type_Lock,
+	"USE":       ksql.Sql_Type_Use,
+	"HELP":      ksql.Sql_Type_Show,
+	"GRANT":     ksql.Sql_Type_Grant,
+	"REVOKE":    ksql.Sql_Type_Revoke,
+	"FLUSH":     ksql.Sql_Type_Flush,
+	"KILL":      ksql.Sql_Type_Kill,
+	"LOAD":      ksql.Sql_Type_Load,
+	"ANALYZE":   ksql.Sql_Type_Maintenance,
+	"OPTIMIZE":  ksql.Sql_Type_Maintenance,
+	"CHECK":     ksql.Sql_Type_Maintenance,
+	"CHECKSUM":  ksql.Sql_Type_Maintenance,
+	"REPAIR":    ksql.Sql_Type_Maintenance,
+	"HANDLER":   ksql.Sql_Type_Handler,
+	"XA":        ksql.Sql_Type_Xa,
+}
+
+// classify returns the statement type and whether more than one statement is present,
+// an unknown leading keyword is Sql_Type_Unknown and an empty statement is a query
+func classify(raw string) (ksql.SqlType, bool) {
+	l := newLexer(raw)
+	typ := ksql.Sql_Type_Query
+	word, _ := l.next()
+	if word == "WITH" {
+		typ = l.withBody()
+	} else if t, ok := keywords[word]; ok {
+		typ = t
+	} else if word != "" {
+		typ = ksql.Sql_Type_Unknown
+	}
+
+	for !l.semi && l.pos < len(l.raw) {
+		l.next()
+	}
+
+	return typ, l.semi && l.rest()
+}
+
+// withBody finds the statement following the common table expressions of WITH
+func (l *lexer) withBody() ksql.SqlType {
+	for {
+		word, depth := l.next()
+		if word == "" {
+			return ksql.Sql_Type_Query
+		}
+		if depth > 0 {
+			continue
+		}
+
+		switch word {
+		case "SELECT", "TABLE", "VALUES":
+			return ksql.Sql_Type_Query
+		case "INSERT", "UPDATE", "DELETE", "REPLACE":
+			return keywords[word]
+		}
+	}
+}
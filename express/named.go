@@ -0,0 +1,221 @@
+package express
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// NamedInterface supplies values for :name / @name placeholders
+type NamedInterface interface {
+	NamedArgs() map[string]any
+}
+
+// NewNamedStatement expands :name and @name placeholders to positional binds, a :name missing from params is an error
+func NewNamedStatement(raw string, params any) (*Statement, error) {
+	tpl, binds, err := Expand(raw, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewStatement(tpl, binds), nil
+}
+
+// Expand rewrites :name and @name placeholders into ?, slices are expanded into ?, ?, ?
+// an empty slice renders NULL, so IN (:ids) with no ids becomes IN (NULL) and matches no row;
+// a :name not found in params is an error, an @name not found in params is kept as a user variable,
+// quoted strings, identifiers, comments, @@system variables and := are left untouched
+func Expand(raw string, params any) (string, []any, error) {
+	args, err := namedArgs(params)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var builder strings.Builder
+	var binds []any
+	count := len(raw)
+	for i := 0; i < count; {
+		c := raw[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end := skipQuoted(raw, i)
+			builder.WriteString(raw[i:end])
+			i = end
+		case isComment(raw, i):
+			end := skipComment(raw, i)
+			builder.WriteString(raw[i:end])
+			i = end
+		case (c == ':' || c == '@') && i+1 < count && isIdentStart(raw[i+1]) && (i == 0 || (raw[i-1] != '@' && raw[i-1] != ':' && !isIdent(raw[i-1]))):
+			end := i + 1
+			for end < count && isIdent(raw[end]) {
+				end++
+			}
+			name := raw[i+1 : end]
+			value, ok := args[name]
+			if !ok {
+				if c == ':' {
+					return "", nil, fmt.Errorf("named param :%s not found", name)
+				}
+				builder.WriteString(raw[i:end])
+				i = end
+				continue
+			}
+
+			binds = expandValue(value, &builder, binds)
+			i = end
+		default:
+			builder.WriteByte(c)
+			i++
+		}
+	}
+
+	return builder.String(), binds, nil
+}
+
+func expandValue(value any, builder *strings.Builder, binds []any) []any {
+	if _, ok := value.(driver.Valuer); ok {
+		builder.WriteString("?")
+		return append(binds, value)
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice || v.Type().Elem().Kind() == reflect.Uint8 {
+		builder.WriteString("?")
+		return append(binds, value)
+	}
+
+	if v.Len() == 0 {
+		builder.WriteString("NULL")
+		return binds
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		builder.WriteString("?")
+		binds = append(binds, v.Index(i).Interface())
+	}
+
+	return binds
+}
+
+func namedArgs(params any) (map[string]any, error) {
+	switch tmp := params.(type) {
+	case nil:
+		return nil, nil
+	case map[string]any:
+		return tmp, nil
+	case NamedInterface:
+		return tmp.NamedArgs(), nil
+	}
+
+	v := reflect.ValueOf(params)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("named params map key must be string, got %s", v.Type().Key())
+		}
+		args := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			args[iter.Key().String()] = iter.Value().Interface()
+		}
+		return args, nil
+	case reflect.Struct:
+		args := make(map[string]any)
+		structArgs(v, args)
+		return args, nil
+	}
+
+	return nil, fmt.Errorf("named params type %T not support", params)
+}
+
+// fields are named by db tag or field name, db:"-" and unexported fields are skipped
+func structArgs(v reflect.Value, args map[string]any) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Tag.Get("db")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			fv := v.Field(i)
+			if fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				structArgs(fv, args)
+				continue
+			}
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		args[name] = v.Field(i).Interface()
+	}
+}
+
+func skipQuoted(raw string, start int) int {
+	quote := raw[start]
+	for i := start + 1; i < len(raw); i++ {
+		switch raw[i] {
+		case '\\':
+			if quote != '`' {
+				i++
+			}
+		case quote:
+			if i+1 < len(raw) && raw[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+
+	return len(raw)
+}
+
+func skipComment(raw string, start int) int {
+	if raw[start] == '/' {
+		if end := strings.Index(raw[start+2:], "*/"); end >= 0 {
+			return start + 2 + end + 2
+		}
+		return len(raw)
+	}
+
+	if end := strings.IndexByte(raw[start:], '\n'); end >= 0 {
+		return start + end + 1
+	}
+
+	return len(raw)
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\v' || c == '\f'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdent(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,64 @@
+package express
+
+import (
+	"testing"
+	"time"
+
+	ksql "github.com/kovey/db-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+type namedUser struct {
+	Id     int    `db:"id"`
+	Name   string `db:"name"`
+	Secret string `db:"-"`
+	Age    int
+	hidden int
+}
+
+type namedParams map[string]any
+
+func (n namedParams) NamedArgs() map[string]any {
+	return n
+}
+
+func TestExpandMap(t *testing.T) {
+	raw, binds, err := Expand("SELECT * FROM user WHERE status = :status AND id IN (:ids) AND (name = @name OR nick = @name) AND created > :since", map[string]any{
+		"status": 1, "ids": []int64{1, 2, 3}, "name": "kovey", "since": time.Time{},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM user WHERE status = ? AND id IN (?, ?, ?) AND (name = ? OR nick = ?) AND created > ?", raw)
+	assert.Equal(t, []any{1, int64(1), int64(2), int64(3), "kovey", "kovey", time.Time{}}, binds)
+}
+
+func TestExpandSkip(t *testing.T) {
+	raw, binds, err := Expand("SELECT ':id', \"@id\", `:id`, @@session.time_zone, @rownum := @rownum + 1, :id /* :id */ -- :id\n# :id\nFROM t WHERE a IN (:empty) AND b = :bin", map[string]any{"id": 1, "empty": []string{}, "bin": []byte("x")})
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT ':id', \"@id\", `:id`, @@session.time_zone, @rownum := @rownum + 1, ? /* :id */ -- :id\n# :id\nFROM t WHERE a IN (NULL) AND b = ?", raw)
+	assert.Equal(t, []any{1, []byte("x")}, binds)
+
+	_, _, err = Expand("SELECT * FROM t WHERE id = :missing", map[string]any{})
+	assert.Equal(t, "named param :missing not found", err.Error())
+	_, _, err = Expand("SELECT 1", 1)
+	assert.NotNil(t, err)
+}
+
+func TestExpandStruct(t *testing.T) {
+	raw, binds, err := Expand("UPDATE user SET name = :name, age = :Age WHERE id = :id", &namedUser{Id: 1, Name: "kovey", Age: 18, Secret: "x"})
+	assert.Nil(t, err)
+	assert.Equal(t, "UPDATE user SET name = ?, age = ? WHERE id = ?", raw)
+	assert.Equal(t, []any{"kovey", 18, 1}, binds)
+
+	_, _, err = Expand("SELECT :Secret", namedUser{})
+	assert.NotNil(t, err)
+}
+
+func TestNamedStatement(t *testing.T) {
+	s, err := NewNamedStatement("DELETE FROM user WHERE id = :id", namedParams{"id": 2})
+	assert.Nil(t, err)
+	assert.Equal(t, ksql.Sql_Type_Delete, s.Type())
+	assert.Equal(t, "DELETE FROM user WHERE id = ?", s.Statement())
+	assert.Equal(t, []any{2}, s.Binds())
+	_, err = NewNamedStatement("SELECT :a", nil)
+	assert.NotNil(t, err)
+}
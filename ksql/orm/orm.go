@@ -32,8 +32,11 @@ func create(ctx context.Context, conn ksql.ConnectionInterface, table schema.Tab
 		f.CanNull = column.Nullable()
 		tpl.Fields = append(tpl.Fields, f)
 		tpl.Consts = append(tpl.Consts, constInfo{Table: tpl.Name, Name: column.Name(), Column: f.Name, Comment: f.Comment})
-		if f.Type == "time.Time" {
-			tpl.Imports = append(tpl.Imports, "time")
+		switch {
+		case f.Type == "time.Time":
+			tpl.addImport("time")
+		case strings.HasPrefix(f.Type, "db.JSON["):
+			tpl.addImport("github.com/kovey/db-go/v3/db")
 		}
 		if column.Key() == "PRI" {
 			tpl.PrimaryId = fmt.Sprintf("Table_%s_%s", tpl.Name, f.Name)
@@ -149,8 +152,10 @@ func getType(mysqlType string) string {
 	case "DATE":
 		return "time.Time"
 	case "TINYBLOB", "TINYTEXT", "BLOB", "TEXT", "MEDIUMBLOB", "MEDIUMTEXT", "LONGBLOB", "LONGBTEXT", "GEOMETRY", "POINT", "LINESTRING", "POLYGON", "MULTIPOINT",
-		"MULTILINESTRING", "MULTIPOLYGON", "GEOMETRYCOLLECTION", "JSON":
+		"MULTILINESTRING", "MULTIPOLYGON", "GEOMETRYCOLLECTION":
 		return "string"
+	case "JSON":
+		return "db.JSON[any]"
 	case "ENUM":
 		return "int16"
 	case "SET":
@@ -103,6 +103,16 @@ type modelTpl struct {
 	Sql         string
 }
 
+func (m *modelTpl) addImport(path string) {
+	for _, imp := range m.Imports {
+		if imp == path {
+			return
+		}
+	}
+
+	m.Imports = append(m.Imports, path)
+}
+
 func (m *modelTpl) Parse() ([]byte, error) {
 	t := template.Must(template.New("main_tpl").Funcs(template.FuncMap{"safe": func(tag string) template.HTML {
 		return template.HTML(tag)
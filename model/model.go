@@ -205,7 +205,8 @@ func (m *Model) toData(model ksql.ModelInterface) *db.Data {
 }
 
 func (m *Model) insert(ctx context.Context, data *db.Data) (int64, error) {
-	if m.conn == nil {
+	conn := m._writeConn(ctx)
+	if conn == nil {
 		return db.Insert(ctx, m.table, data)
 	}
 
@@ -219,13 +220,19 @@ func (m *Model) insert(ctx context.Context, data *db.Data) (int64, error) {
 		op.Add(key, val)
 	})
 
-	return m.conn.Insert(ctx, op)
+	id, err := conn.Insert(ctx, op)
+	if err == nil {
+		db.InvalidateCacheBy(ctx, conn, m.table)
+	}
+
+	return id, err
 }
 
 func (m *Model) update(ctx context.Context, data *db.Data) (int64, error) {
 	w := db.NewWhere()
 	w.Where(m.primaryId, "=", m.data.Get(m.primaryId))
-	if m.conn == nil {
+	conn := m._writeConn(ctx)
+	if conn == nil {
 		return db.Update(ctx, m.table, data, w)
 	}
 
@@ -239,16 +246,26 @@ func (m *Model) update(ctx context.Context, data *db.Data) (int64, error) {
 		u.Set(key, val)
 	})
 	u.Where(w)
-	return m.conn.Update(ctx, u)
+	affected, err := conn.Update(ctx, u)
+	if err == nil {
+		db.InvalidateCacheBy(ctx, conn, m.Table())
+	}
+
+	return affected, err
 }
 
-func (m *Model) _conn() ksql.ConnectionInterface {
-	if m.conn == nil {
-		database, _ := db.Get()
-		return database
+// the transaction of ctx wins over the connection the model was fetched with
+func (m *Model) _writeConn(ctx context.Context) ksql.ConnectionInterface {
+	return db.ConnOf(ctx, m.conn)
+}
+
+func (m *Model) _conn(ctx context.Context) ksql.ConnectionInterface {
+	if conn := m._writeConn(ctx); conn != nil {
+		return conn
 	}
 
-	return m.conn
+	database, _ := db.Get()
+	return database
 }
 
 func (m *Model) SaveBy(ctx context.Context, model ksql.ModelInterface) error {
@@ -257,11 +274,11 @@ func (m *Model) SaveBy(ctx context.Context, model ksql.ModelInterface) error {
 	}
 
 	if !m.fromFecth {
-		if err := m.OnCreateBefore(m._conn()); err != nil {
+		if err := m.OnCreateBefore(m._conn(ctx)); err != nil {
 			return err
 		}
 	} else {
-		if err := m.OnUpdateBefore(m._conn()); err != nil {
+		if err := m.OnUpdateBefore(m._conn(ctx)); err != nil {
 			return err
 		}
 	}
@@ -277,7 +294,7 @@ func (m *Model) SaveBy(ctx context.Context, model ksql.ModelInterface) error {
 		m.setPrimary(model, id)
 		m.fromFecth = true
 		m.isInitialized = true
-		return m.OnCreateAfter(m._conn())
+		return m.OnCreateAfter(m._conn(ctx))
 	}
 
 	id, err := m.update(ctx, data)
@@ -290,7 +307,7 @@ func (m *Model) SaveBy(ctx context.Context, model ksql.ModelInterface) error {
 	}
 
 	m.data.From(data)
-	return m.OnUpdateAfter(m._conn())
+	return m.OnUpdateAfter(m._conn(ctx))
 }
 
 func (m *Model) primaryValue(model ksql.ModelInterface) any {
@@ -305,13 +322,14 @@ func (m *Model) primaryValue(model ksql.ModelInterface) any {
 }
 
 func (m *Model) DeleteBy(ctx context.Context, model ksql.ModelInterface) error {
-	if err := m.OnDeleteBefore(m._conn()); err != nil {
+	if err := m.OnDeleteBefore(m._conn(ctx)); err != nil {
 		return err
 	}
 
 	w := db.NewWhere()
 	w.Where(m.primaryId, "=", m.primaryValue(model))
-	if m.conn == nil {
+	conn := m._writeConn(ctx)
+	if conn == nil {
 		id, err := db.Delete(ctx, m.table, w)
 		if err != nil {
 			return err
@@ -321,21 +339,23 @@ func (m *Model) DeleteBy(ctx context.Context, model ksql.ModelInterface) error {
 			return Err_Affect_No_Rows
 		}
 
-		return m.OnDeleteAfter(m._conn())
+		return m.OnDeleteAfter(m._conn(ctx))
 	}
 
 	op := db.NewDelete()
 	op.Table(m.table).Where(w)
-	id, err := m.conn.Delete(ctx, op)
+	id, err := conn.Delete(ctx, op)
 	if err != nil {
 		return err
 	}
 
+	db.InvalidateCacheBy(ctx, conn, m.table)
+
 	if id == 0 {
 		return Err_Affect_No_Rows
 	}
 
-	return m.OnDeleteAfter(m._conn())
+	return m.OnDeleteAfter(m._conn(ctx))
 }
 
 func Rows[T ksql.ModelInterface](models *[]T) ksql.BuilderInterface[T] {
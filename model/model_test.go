@@ -3,6 +3,7 @@ package model
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	ksql "github.com/kovey/db-go/v3"
@@ -327,3 +328,83 @@ func TestModelInsertNoAutoInc(t *testing.T) {
 	}
 	assert.Nil(t, mock.ExpectationsWereMet())
 }
+
+func TestModelSaveInTransaction(t *testing.T) {
+	testDb, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.Nil(t, err)
+	defer testDb.Close()
+	otherDb, otherMock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.Nil(t, err)
+	defer otherDb.Close()
+
+	conn, err := db.Open(testDb, "mysql")
+	assert.Nil(t, err)
+	other, err := db.Open(otherDb, "mysql")
+	assert.Nil(t, err)
+	same, err := db.Open(testDb, "mysql")
+	assert.Nil(t, err)
+	m := newTestmModel()
+	m.WithConn(same)
+	m.Age = 18
+	m.Name = "kovey"
+	remote := newTestmModel()
+	remote.WithConn(other)
+	remote.Age = 20
+	remote.Name = "remote"
+
+	mock.ExpectBegin()
+	mock.ExpectPrepare("INSERT INTO `user` (`age`, `name`, `create_time`, `sex`) VALUES (?, ?, ?, ?)").ExpectExec().WithArgs(18, "kovey", "", nil).WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectPrepare("DELETE FROM `user` WHERE `id` = ?").ExpectExec().WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	otherMock.ExpectPrepare("INSERT INTO `user` (`age`, `name`, `create_time`, `sex`) VALUES (?, ?, ?, ?)").ExpectExec().WithArgs(20, "remote", "", nil).WillReturnResult(sqlmock.NewResult(3, 1))
+	txErr := conn.Transaction(context.Background(), func(ctx context.Context, tx ksql.ConnectionInterface) error {
+		if err := m.Save(ctx); err != nil {
+			return err
+		}
+
+		// a model of another database is not written in the transaction
+		if err := remote.Save(ctx); err != nil {
+			return err
+		}
+
+		return m.Delete(ctx)
+	})
+	assert.Nil(t, txErr)
+	assert.Equal(t, 2, m.Id)
+	assert.Equal(t, 3, remote.Id)
+	assert.Nil(t, mock.ExpectationsWereMet())
+	assert.Nil(t, otherMock.ExpectationsWereMet())
+}
+
+func TestModelCacheInvalidate(t *testing.T) {
+	testDb, mock, err := sqlmock.NewWithDSN("root:123456@tcp(127.0.0.1:3306)/test_dev?charset=utf8mb4&parseTime=true", sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.Nil(t, err)
+	defer testDb.Close()
+
+	conn, err := db.Open(testDb, "mysql")
+	assert.Nil(t, err)
+	db.SetCacheStore(db.NewLruStore(16))
+	defer db.SetCacheStore(db.NewLruStore(db.Cache_Default_Size))
+
+	mock.ExpectPrepare("SELECT `id`, `age`, `name`, `create_time`, `sex` FROM `user` WHERE `id` = ?").ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows(newTestmModel().Columns()).AddRow(1, 18, "kovey", "2025-04-03 11:11:11", 1))
+	mock.ExpectPrepare("UPDATE `user` SET `age` = ? WHERE `id` = ?").ExpectExec().WithArgs(19, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare("SELECT `id`, `age`, `name`, `create_time`, `sex` FROM `user` WHERE `id` = ?").ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows(newTestmModel().Columns()).AddRow(1, 19, "kovey", "2025-04-03 11:11:11", 1))
+	find := func() *test_model {
+		m := newTestmModel()
+		m.WithConn(conn)
+		assert.Nil(t, db.Model(m).Where("id", ksql.Eq, 1).Cache(time.Minute, "user:1").First(context.Background()))
+		return m
+	}
+
+	m := find()
+	*m.Sex = 2
+	cached := find()
+	assert.Equal(t, 18, cached.Age)
+	assert.Equal(t, 1, *cached.Sex)
+	assert.NotSame(t, m.Sex, cached.Sex)
+
+	cached.Age = 19
+	assert.Nil(t, cached.Save(context.Background()))
+	assert.Equal(t, 19, find().Age)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
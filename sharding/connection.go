@@ -3,22 +3,50 @@ package sharding
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"sync"
+	"time"
 
 	ksql "github.com/kovey/db-go/v3"
+	"github.com/kovey/db-go/v3/db"
 )
 
 type baseConnection struct {
 	conns      []ksql.ConnectionInterface
 	count      int
 	driverName string
+	locker     sync.RWMutex
+	health     *healthChecker
 }
 
 func (b *baseConnection) first() ksql.ConnectionInterface {
-	return b.conns[0]
+	return b.at(0)
 }
 
 func (b *baseConnection) conn(key any) ksql.ConnectionInterface {
-	return b.conns[b.node(key)]
+	return b.at(b.node(key))
+}
+
+func (b *baseConnection) at(index int) ksql.ConnectionInterface {
+	b.locker.RLock()
+	defer b.locker.RUnlock()
+	return b.conns[index]
+}
+
+func (b *baseConnection) replace(index int, conn ksql.ConnectionInterface) ksql.ConnectionInterface {
+	b.locker.Lock()
+	defer b.locker.Unlock()
+	old := b.conns[index]
+	b.conns[index] = conn
+	return old
+}
+
+func (b *baseConnection) all() []ksql.ConnectionInterface {
+	b.locker.RLock()
+	defer b.locker.RUnlock()
+	conns := make([]ksql.ConnectionInterface, len(b.conns))
+	copy(conns, b.conns)
+	return conns
 }
 
 func (b *baseConnection) node(key any) int {
@@ -26,8 +54,12 @@ func (b *baseConnection) node(key any) int {
 }
 
 func (b *baseConnection) Close() error {
+	if b.health != nil {
+		b.health.stop()
+	}
+
 	var err error
-	for _, conn := range b.conns {
+	for _, conn := range b.all() {
 		err = conn.Database().Close()
 	}
 
@@ -35,7 +67,7 @@ func (b *baseConnection) Close() error {
 }
 
 func (b *baseConnection) Range(call func(index int, conn ksql.ConnectionInterface) error) error {
-	for index, conn := range b.conns {
+	for index, conn := range b.all() {
 		if err := call(index, conn); err != nil {
 			return err
 		}
@@ -44,11 +76,88 @@ func (b *baseConnection) Range(call func(index int, conn ksql.ConnectionInterfac
 	return nil
 }
 
+func (b *baseConnection) Stats() []sql.DBStats {
+	conns := b.all()
+	stats := make([]sql.DBStats, len(conns))
+	for index, conn := range conns {
+		stats[index] = conn.Database().Stats()
+	}
+
+	return stats
+}
+
+func (b *baseConnection) BreakerStates() []db.BreakerState {
+	conns := b.all()
+	states := make([]db.BreakerState, len(conns))
+	for index, conn := range conns {
+		states[index] = db.BreakerStateOf(conn)
+	}
+
+	return states
+}
+
+func (b *baseConnection) StmtCacheStats() []db.StmtCacheStats {
+	conns := b.all()
+	stats := make([]db.StmtCacheStats, len(conns))
+	for index, conn := range conns {
+		stats[index] = db.StmtCacheStatsOf(conn)
+	}
+
+	return stats
+}
+
+func (b *baseConnection) SetMaxOpenConns(n int) {
+	for _, conn := range b.all() {
+		conn.Database().SetMaxOpenConns(n)
+	}
+}
+
+func (b *baseConnection) SetMaxIdleConns(n int) {
+	for _, conn := range b.all() {
+		conn.Database().SetMaxIdleConns(n)
+	}
+}
+
+func (b *baseConnection) SetConnMaxLifetime(d time.Duration) {
+	for _, conn := range b.all() {
+		conn.Database().SetConnMaxLifetime(d)
+	}
+}
+
+func (b *baseConnection) SetConnMaxIdleTime(d time.Duration) {
+	for _, conn := range b.all() {
+		conn.Database().SetConnMaxIdleTime(d)
+	}
+}
+
+// SetNodePool changes the pool of node by the non zero pool fields of conf, the others are kept
+func (b *baseConnection) SetNodePool(node int, conf db.Config) error {
+	if node < 0 || node >= b.count {
+		return Err_Node_Out_Of_Range
+	}
+
+	pool := b.at(node).Database()
+	if conf.MaxIdleTime != 0 {
+		pool.SetConnMaxIdleTime(conf.MaxIdleTime)
+	}
+	if conf.MaxLifeTime != 0 {
+		pool.SetConnMaxLifetime(conf.MaxLifeTime)
+	}
+	if conf.MaxIdleConns != 0 {
+		pool.SetMaxIdleConns(conf.MaxIdleConns)
+	}
+	if conf.MaxOpenConns != 0 {
+		pool.SetMaxOpenConns(conf.MaxOpenConns)
+	}
+	return nil
+}
+
 type Connection struct {
 	*baseConnection
 	currents      map[any]ksql.ConnectionInterface
 	inTransaction bool
 	keys          []any
+	points        int
 }
 
 func (c *Connection) Clone() ConnectionInterface {
@@ -194,6 +303,13 @@ func (c *Connection) TransactionBy(ctx context.Context, keys []any, options *sql
 		return err
 	}
 
+	defer func() {
+		if r := recover(); r != nil {
+			c.Rollback(ctx)
+			panic(r)
+		}
+	}()
+
 	if err := call(ctx, c); err != nil {
 		if txErr := c.Rollback(ctx); txErr != nil {
 			tmp := txErr.(*TxErr)
@@ -206,6 +322,97 @@ func (c *Connection) TransactionBy(ctx context.Context, keys []any, options *sql
 	return c.Commit(ctx)
 }
 
+// BeginTo creates the savepoint on every node of the transaction,
+// when a node fails the savepoint is released on the nodes it was created on
+func (c *Connection) BeginTo(ctx context.Context, point string) ksql.TxError {
+	if !c.inTransaction {
+		return newTxErr().AppendBegin(nil, db.Err_Not_In_Transaction)
+	}
+
+	for i, key := range c.keys {
+		if err := c.currents[key].BeginTo(ctx, point); err != nil {
+			txErr := newTxErr().AppendBegin(key, err)
+			for i--; i >= 0; i-- {
+				if err := c.currents[c.keys[i]].CommitTo(ctx, point); err != nil {
+					txErr.AppendCommit(c.keys[i], err)
+				}
+			}
+
+			return txErr
+		}
+	}
+
+	return nil
+}
+
+// RollbackTo rolls every node of the transaction back to the savepoint
+func (c *Connection) RollbackTo(ctx context.Context, point string) ksql.TxError {
+	var txErr *TxErr
+	for _, key := range c.keys {
+		if err := c.currents[key].RollbackTo(ctx, point); err != nil {
+			if txErr == nil {
+				txErr = newTxErr()
+			}
+
+			txErr.AppendRollback(key, err)
+		}
+	}
+
+	if txErr == nil {
+		return nil
+	}
+
+	return txErr
+}
+
+// CommitTo releases the savepoint on every node of the transaction
+func (c *Connection) CommitTo(ctx context.Context, point string) ksql.TxError {
+	var txErr *TxErr
+	for _, key := range c.keys {
+		if err := c.currents[key].CommitTo(ctx, point); err != nil {
+			if txErr == nil {
+				txErr = newTxErr()
+			}
+
+			txErr.AppendCommit(key, err)
+		}
+	}
+
+	if txErr == nil {
+		return nil
+	}
+
+	return txErr
+}
+
+// Savepoint runs call inside a savepoint on every node of the transaction, the savepoint is rolled back
+// when call fails or panics and released otherwise
+func (c *Connection) Savepoint(ctx context.Context, call func(ctx context.Context, conn ConnectionInterface) error) ksql.TxError {
+	c.points++
+	point := fmt.Sprintf("sharding_%d", c.points)
+	defer func() { c.points-- }()
+	if err := c.BeginTo(ctx, point); err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			c.RollbackTo(ctx, point)
+			panic(r)
+		}
+	}()
+
+	if err := call(ctx, c); err != nil {
+		if txErr := c.RollbackTo(ctx, point); txErr != nil {
+			return txErr.(*TxErr).AppendCall(err)
+		}
+
+		return newTxErr().AppendCall(err)
+	}
+
+	return c.CommitTo(ctx, point)
+}
+
 func (c *Connection) ScanRaw(key any, ctx context.Context, raw ksql.ExpressInterface, data ...any) error {
 	return c.Get(key).ScanRaw(ctx, raw, data...)
 }
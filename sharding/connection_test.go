@@ -3,9 +3,12 @@ package sharding
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
 	"github.com/kovey/db-go/v3/db"
 	"github.com/stretchr/testify/assert"
 )
@@ -197,3 +200,179 @@ func TestConnectionTransaction(t *testing.T) {
 	assert.Nil(t, mock1.ExpectationsWereMet())
 	assert.Nil(t, mock2.ExpectationsWereMet())
 }
+
+func TestConnectionTransactionPanic(t *testing.T) {
+	testDb1, mock1, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.Nil(t, err)
+	testDb2, mock2, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.Nil(t, err)
+	defer testDb1.Close()
+	defer testDb2.Close()
+	err = InitBy("mysql", []*sql.DB{testDb1, testDb2})
+	assert.Nil(t, err)
+
+	mock1.ExpectBegin()
+	mock1.ExpectRollback()
+	mock2.ExpectBegin()
+	mock2.ExpectRollback()
+	conn := database.Clone()
+	assert.PanicsWithValue(t, "boom", func() {
+		conn.Transaction(context.Background(), []any{1, 2}, func(ctx context.Context, conn ConnectionInterface) error {
+			panic("boom")
+		})
+	})
+	assert.False(t, conn.InTransaction())
+	assert.Nil(t, mock1.ExpectationsWereMet())
+	assert.Nil(t, mock2.ExpectationsWereMet())
+}
+
+func TestConnectionSavepoint(t *testing.T) {
+	testDb1, mock1, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.Nil(t, err)
+	testDb2, mock2, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.Nil(t, err)
+	defer testDb1.Close()
+	defer testDb2.Close()
+	err = InitBy("mysql", []*sql.DB{testDb1, testDb2})
+	assert.Nil(t, err)
+
+	for _, mock := range []sqlmock.Sqlmock{mock1, mock2} {
+		mock.ExpectBegin()
+		mock.ExpectExec("SAVEPOINT `sharding_1`").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("ROLLBACK TO SAVEPOINT `sharding_1`").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("SAVEPOINT `sharding_1`").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("RELEASE SAVEPOINT `sharding_1`").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+	}
+
+	conn := database.Clone()
+	assert.NotNil(t, conn.BeginTo(context.Background(), "a"))
+	txErr := conn.Transaction(context.Background(), []any{1, 2}, func(ctx context.Context, conn ConnectionInterface) error {
+		err := conn.Savepoint(ctx, func(ctx context.Context, conn ConnectionInterface) error {
+			return fmt.Errorf("failed")
+		})
+		assert.Equal(t, "failed", err.Call().Error())
+		assert.Nil(t, conn.Savepoint(ctx, func(ctx context.Context, conn ConnectionInterface) error {
+			return nil
+		}))
+		return nil
+	})
+	assert.Nil(t, txErr)
+	assert.Nil(t, mock1.ExpectationsWereMet())
+	assert.Nil(t, mock2.ExpectationsWereMet())
+}
+
+func TestConnectionSavepointPartial(t *testing.T) {
+	testDb1, mock1, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.Nil(t, err)
+	testDb2, mock2, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.Nil(t, err)
+	defer testDb1.Close()
+	defer testDb2.Close()
+	err = InitBy("mysql", []*sql.DB{testDb1, testDb2})
+	assert.Nil(t, err)
+
+	// key 1 is on node 1 and gets the savepoint, key 2 fails on node 0 so node 1 releases it
+	failed := fmt.Errorf("savepoint failed")
+	mock2.ExpectBegin()
+	mock2.ExpectExec("SAVEPOINT `sharding_1`").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock2.ExpectExec("RELEASE SAVEPOINT `sharding_1`").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock2.ExpectExec("SAVEPOINT `sharding_1`").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock2.ExpectExec("RELEASE SAVEPOINT `sharding_1`").WillReturnError(fmt.Errorf("release failed"))
+	mock2.ExpectCommit()
+	mock1.ExpectBegin()
+	mock1.ExpectExec("SAVEPOINT `sharding_1`").WillReturnError(failed)
+	mock1.ExpectExec("SAVEPOINT `sharding_1`").WillReturnError(failed)
+	mock1.ExpectCommit()
+
+	called := false
+	txErr := database.Clone().Transaction(context.Background(), []any{1, 2}, func(ctx context.Context, conn ConnectionInterface) error {
+		err := conn.Savepoint(ctx, func(ctx context.Context, conn ConnectionInterface) error {
+			called = true
+			return nil
+		})
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Begin().Error(), "savepoint failed on key 2")
+
+		// a failed release is a commit error of the savepoint
+		err = conn.Savepoint(ctx, func(ctx context.Context, conn ConnectionInterface) error {
+			called = true
+			return nil
+		})
+		assert.Contains(t, err.Begin().Error(), "savepoint failed on key 2")
+		assert.Contains(t, err.Commit().Error(), "release failed on key 1")
+		assert.Equal(t, "", err.Rollback().Error())
+		return nil
+	})
+	assert.Nil(t, txErr)
+	assert.False(t, called)
+	assert.Nil(t, mock1.ExpectationsWereMet())
+	assert.Nil(t, mock2.ExpectationsWereMet())
+}
+
+func TestConnectionPool(t *testing.T) {
+	dsn1 := fmt.Sprintf("pool_0_%d", time.Now().UnixNano())
+	dsn2 := fmt.Sprintf("pool_1_%d", time.Now().UnixNano())
+	testDb1, _, err := sqlmock.NewWithDSN(dsn1)
+	assert.Nil(t, err)
+	testDb2, _, err := sqlmock.NewWithDSN(dsn2)
+	assert.Nil(t, err)
+	defer testDb1.Close()
+	defer testDb2.Close()
+
+	err = Init([]db.Config{
+		{DriverName: "sqlmock", DataSourceName: dsn1, MaxIdleConns: 2, MaxOpenConns: 7},
+		{DriverName: "sqlmock", DataSourceName: dsn2, MaxIdleConns: 3, MaxOpenConns: 9},
+	})
+	assert.Nil(t, err)
+	defer Close()
+
+	stats := Get().Stats()
+	assert.Equal(t, 2, len(stats))
+	assert.Equal(t, 7, stats[0].MaxOpenConnections)
+	assert.Equal(t, 9, stats[1].MaxOpenConnections)
+
+	SetMaxOpenConns(20)
+	stats = Stats()
+	assert.Equal(t, 20, stats[0].MaxOpenConnections)
+	assert.Equal(t, 20, stats[1].MaxOpenConnections)
+
+	assert.Nil(t, SetNodePool(1, db.Config{MaxIdleConns: 1, MaxOpenConns: 5}))
+	stats = Stats()
+	assert.Equal(t, 20, stats[0].MaxOpenConnections)
+	assert.Equal(t, 5, stats[1].MaxOpenConnections)
+
+	// zero fields keep the current setting
+	assert.Nil(t, SetNodePool(1, db.Config{MaxIdleConns: 2}))
+	assert.Equal(t, 5, Stats()[1].MaxOpenConnections)
+	assert.Equal(t, Err_Node_Out_Of_Range, SetNodePool(2, db.Config{}))
+}
+
+func TestConnectionRetry(t *testing.T) {
+	dsn1 := fmt.Sprintf("retry_0_%d", time.Now().UnixNano())
+	dsn2 := fmt.Sprintf("retry_1_%d", time.Now().UnixNano())
+	testDb1, _, err := sqlmock.NewWithDSN(dsn1, sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.Nil(t, err)
+	testDb2, mock2, err := sqlmock.NewWithDSN(dsn2, sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.Nil(t, err)
+	defer testDb1.Close()
+	defer testDb2.Close()
+
+	// the retry policy of a node config applies to the connection of that node
+	policy := db.DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+	err = Init([]db.Config{
+		{DriverName: "sqlmock", DataSourceName: dsn1},
+		{DriverName: "sqlmock", DataSourceName: dsn2, MaxIdleConns: 1, Retry: policy},
+	})
+	assert.Nil(t, err)
+	defer Close()
+
+	mock2.ExpectPrepare("SELECT `id`, `user_id`, `age`, `name` FROM `user_1` WHERE `user_id` = ?").WillReturnError(&mysql.MySQLError{Number: db.Mysql_Err_Deadlock})
+	mock2.ExpectPrepare("SELECT `id`, `user_id`, `age`, `name` FROM `user_1` WHERE `user_id` = ?").ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows(newTestModel().Columns()).AddRow(1, 1, 19, "kovey"))
+	var tms []*test_model
+	err = QueryRaw(1, context.Background(), db.Raw("SELECT `id`, `user_id`, `age`, `name` FROM `user_1` WHERE `user_id` = ?", 1), &tms)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(tms))
+	assert.Nil(t, mock2.ExpectationsWereMet())
+}
@@ -3,27 +3,22 @@ package sharding
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 
 	ksql "github.com/kovey/db-go/v3"
 	"github.com/kovey/db-go/v3/db"
 )
 
+var Err_Node_Out_Of_Range = errors.New("node out of range")
+
 var database *Connection
 
 func Init(configs []db.Config) error {
 	conn := &Connection{baseConnection: &baseConnection{conns: make([]ksql.ConnectionInterface, len(configs))}, currents: make(map[any]ksql.ConnectionInterface)}
 	for index, conf := range configs {
-		dbConn, err := sql.Open(conf.DriverName, conf.DataSourceName)
-		if err != nil {
-			return err
-		}
-
-		dbConn.SetConnMaxIdleTime(conf.MaxIdleTime)
-		dbConn.SetConnMaxLifetime(conf.MaxLifeTime)
-		dbConn.SetMaxIdleConns(conf.MaxIdleConns)
-		dbConn.SetMaxOpenConns(conf.MaxIdleConns)
-		c, err := db.Open(dbConn, conf.DriverName)
+		c, err := open(conf)
 		if err != nil {
 			return err
 		}
@@ -37,6 +32,22 @@ func Init(configs []db.Config) error {
 	return nil
 }
 
+func open(conf db.Config) (ksql.ConnectionInterface, error) {
+	dbConn, err := sql.Open(conf.DriverName, conf.DataSourceName)
+	if err != nil {
+		return nil, err
+	}
+
+	db.ApplyPool(dbConn, conf)
+	c, err := db.OpenBy(dbConn, conf)
+	if err != nil {
+		dbConn.Close()
+		return nil, err
+	}
+
+	return c, nil
+}
+
 func InitBy(driverName string, conns []*sql.DB) error {
 	conn := &Connection{baseConnection: &baseConnection{conns: make([]ksql.ConnectionInterface, len(conns)), driverName: driverName}, currents: make(map[any]ksql.ConnectionInterface)}
 	for index, co := range conns {
@@ -61,6 +72,38 @@ func Get() ConnectionInterface {
 	return database.Clone()
 }
 
+func Stats() []sql.DBStats {
+	return database.Stats()
+}
+
+func BreakerStates() []db.BreakerState {
+	return database.BreakerStates()
+}
+
+func StmtCacheStats() []db.StmtCacheStats {
+	return database.StmtCacheStats()
+}
+
+func SetMaxOpenConns(n int) {
+	database.SetMaxOpenConns(n)
+}
+
+func SetMaxIdleConns(n int) {
+	database.SetMaxIdleConns(n)
+}
+
+func SetConnMaxLifetime(d time.Duration) {
+	database.SetConnMaxLifetime(d)
+}
+
+func SetConnMaxIdleTime(d time.Duration) {
+	database.SetConnMaxIdleTime(d)
+}
+
+func SetNodePool(node int, conf db.Config) error {
+	return database.SetNodePool(node, conf)
+}
+
 func Close() error {
 	if database == nil {
 		return nil
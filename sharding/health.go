@@ -0,0 +1,266 @@
+package sharding
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	ksql "github.com/kovey/db-go/v3"
+	"github.com/kovey/db-go/v3/db"
+)
+
+type NodeEventType byte
+
+const (
+	Node_Event_Down            NodeEventType = 1
+	Node_Event_Up              NodeEventType = 2
+	Node_Event_Failover        NodeEventType = 3
+	Node_Event_Failover_Failed NodeEventType = 4
+)
+
+func (n NodeEventType) String() string {
+	switch n {
+	case Node_Event_Down:
+		return "DOWN"
+	case Node_Event_Up:
+		return "UP"
+	case Node_Event_Failover:
+		return "FAILOVER"
+	case Node_Event_Failover_Failed:
+		return "FAILOVER_FAILED"
+	default:
+		return ""
+	}
+}
+
+type NodeEvent struct {
+	Node    int
+	Type    NodeEventType
+	Standby int
+	Err     error
+	Time    time.Time
+}
+
+type HealthConfig struct {
+	Interval    time.Duration
+	Timeout     time.Duration
+	MaxFailures int
+	EventMax    int
+	// standby configs of each node, promoted in order when the node is down
+	Standbys map[int][]db.Config
+	OnEvent  func(event NodeEvent)
+}
+
+type nodeHealth struct {
+	failures int
+	down     bool
+	standbys []db.Config
+	promoted int
+}
+
+type healthChecker struct {
+	base   *baseConnection
+	conf   HealthConfig
+	nodes  []*nodeHealth
+	events chan NodeEvent
+	sig    chan struct{}
+	wait   sync.WaitGroup
+	once   sync.Once
+	locker sync.RWMutex
+}
+
+func newHealthChecker(base *baseConnection, conf HealthConfig) *healthChecker {
+	if conf.Interval <= 0 {
+		conf.Interval = 5 * time.Second
+	}
+	if conf.Timeout <= 0 {
+		conf.Timeout = time.Second
+	}
+	if conf.MaxFailures < 1 {
+		conf.MaxFailures = 3
+	}
+	if conf.EventMax < 1 {
+		conf.EventMax = 64
+	}
+
+	h := &healthChecker{base: base, conf: conf, nodes: make([]*nodeHealth, base.count), events: make(chan NodeEvent, conf.EventMax), sig: make(chan struct{})}
+	for index := range h.nodes {
+		h.nodes[index] = &nodeHealth{standbys: conf.Standbys[index]}
+	}
+
+	return h
+}
+
+func (h *healthChecker) start() {
+	h.wait.Add(1)
+	go h.loop()
+}
+
+func (h *healthChecker) loop() {
+	defer h.wait.Done()
+	ticker := time.NewTicker(h.conf.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-h.sig:
+			return
+		case <-ticker.C:
+			h.check(context.Background())
+		}
+	}
+}
+
+func (h *healthChecker) stop() {
+	h.once.Do(func() {
+		close(h.sig)
+		h.wait.Wait()
+	})
+}
+
+func (h *healthChecker) check(ctx context.Context) {
+	for index := range h.nodes {
+		h.checkNode(ctx, index)
+	}
+}
+
+func (h *healthChecker) ping(ctx context.Context, conn ksql.ConnectionInterface) error {
+	ctx, cancel := context.WithTimeout(ctx, h.conf.Timeout)
+	defer cancel()
+	return conn.Database().PingContext(ctx)
+}
+
+func (h *healthChecker) checkNode(ctx context.Context, index int) {
+	err := h.ping(ctx, h.base.at(index))
+	events, down := h.record(index, err)
+	for _, event := range events {
+		h.emit(event)
+	}
+
+	if down {
+		h.failover(index)
+	}
+}
+
+// record counts the ping result of node index, the events are emitted by the caller once the lock is released
+func (h *healthChecker) record(index int, err error) ([]NodeEvent, bool) {
+	h.locker.Lock()
+	defer h.locker.Unlock()
+	node := h.nodes[index]
+	if err == nil {
+		node.failures = 0
+		if node.down {
+			node.down = false
+			return []NodeEvent{{Node: index, Type: Node_Event_Up}}, false
+		}
+		return nil, false
+	}
+
+	node.failures++
+	if node.failures < h.conf.MaxFailures {
+		return nil, false
+	}
+
+	if node.down {
+		return nil, true
+	}
+
+	node.down = true
+	return []NodeEvent{{Node: index, Type: Node_Event_Down, Err: err}}, true
+}
+
+// failover promotes the standbys of node index in order, they are opened without holding the lock
+func (h *healthChecker) failover(index int) {
+	for {
+		h.locker.Lock()
+		node := h.nodes[index]
+		if len(node.standbys) == 0 {
+			h.locker.Unlock()
+			return
+		}
+
+		conf := node.standbys[0]
+		node.standbys = node.standbys[1:]
+		node.promoted++
+		promoted := node.promoted
+		h.locker.Unlock()
+
+		conn, err := open(conf)
+		if err != nil {
+			h.emit(NodeEvent{Node: index, Type: Node_Event_Failover_Failed, Standby: promoted, Err: err})
+			continue
+		}
+
+		old := h.base.replace(index, conn)
+		old.Database().Close()
+		h.locker.Lock()
+		node.failures = 0
+		node.down = false
+		h.locker.Unlock()
+		h.emit(NodeEvent{Node: index, Type: Node_Event_Failover, Standby: promoted})
+		return
+	}
+}
+
+// emit must be called without holding the lock, OnEvent may read Healthy
+func (h *healthChecker) emit(event NodeEvent) {
+	event.Time = time.Now()
+	if h.conf.OnEvent != nil {
+		h.conf.OnEvent(event)
+	}
+
+	select {
+	case h.events <- event:
+	default:
+	}
+}
+
+func (h *healthChecker) healthy(index int) bool {
+	h.locker.RLock()
+	defer h.locker.RUnlock()
+	if index < 0 || index >= len(h.nodes) {
+		return false
+	}
+
+	return !h.nodes[index].down
+}
+
+// start background health checking of all nodes,
+// a running checker is stopped before the new one starts
+func StartHealthCheck(conf HealthConfig) error {
+	if database == nil {
+		return db.Err_Database_Not_Initialized
+	}
+
+	StopHealthCheck()
+	database.health = newHealthChecker(database.baseConnection, conf)
+	database.health.start()
+	return nil
+}
+
+func StopHealthCheck() {
+	if database == nil || database.health == nil {
+		return
+	}
+
+	database.health.stop()
+}
+
+func Events() <-chan NodeEvent {
+	if database == nil || database.health == nil {
+		return nil
+	}
+
+	return database.health.events
+}
+
+func Healthy(node int) bool {
+	if database == nil {
+		return false
+	}
+
+	if database.health == nil {
+		return node >= 0 && node < database.count
+	}
+
+	return database.health.healthy(node)
+}
@@ -0,0 +1,99 @@
+package sharding
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kovey/db-go/v3/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthFailover(t *testing.T) {
+	testDb1, mock1, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual), sqlmock.MonitorPingsOption(true))
+	assert.Nil(t, err)
+	testDb2, mock2, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual), sqlmock.MonitorPingsOption(true))
+	assert.Nil(t, err)
+	dsn := fmt.Sprintf("health_standby_%d", time.Now().UnixNano())
+	standby, mock3, err := sqlmock.NewWithDSN(dsn, sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual), sqlmock.MonitorPingsOption(true))
+	assert.Nil(t, err)
+	defer testDb2.Close()
+	defer standby.Close()
+
+	mock1.ExpectPing()
+	mock2.ExpectPing()
+	err = InitBy("mysql", []*sql.DB{testDb1, testDb2})
+	assert.Nil(t, err)
+
+	var events []NodeEvent
+	err = StartHealthCheck(HealthConfig{
+		Interval: time.Hour, MaxFailures: 2,
+		Standbys: map[int][]db.Config{0: {{DriverName: "sqlmock", DataSourceName: dsn, MaxIdleConns: 2}}},
+		OnEvent:  func(event NodeEvent) { events = append(events, event) },
+	})
+	assert.Nil(t, err)
+	defer StopHealthCheck()
+
+	pingErr := errors.New("ping error")
+	mock1.ExpectPing().WillReturnError(pingErr)
+	mock2.ExpectPing()
+	database.health.check(context.Background())
+	assert.Empty(t, events)
+	assert.True(t, Healthy(0))
+
+	mock1.ExpectPing().WillReturnError(pingErr)
+	mock1.ExpectClose()
+	mock2.ExpectPing()
+	mock3.ExpectPing()
+	database.health.check(context.Background())
+	assert.Equal(t, 2, len(events))
+	assert.Equal(t, Node_Event_Down, events[0].Type)
+	assert.Equal(t, 0, events[0].Node)
+	assert.Equal(t, pingErr, events[0].Err)
+	assert.Equal(t, Node_Event_Failover, events[1].Type)
+	assert.Equal(t, 1, events[1].Standby)
+	assert.True(t, Healthy(0))
+	assert.Equal(t, Node_Event_Down, (<-Events()).Type)
+	assert.Equal(t, Node_Event_Failover, (<-Events()).Type)
+
+	mock3.ExpectPrepare("DELETE FROM `user_0` WHERE `user_id` = ?").ExpectExec().WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+	id, err := Delete(2, context.Background(), "user_0", db.NewWhere().Where("user_id", "=", 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, int64(1), id)
+	assert.Nil(t, mock1.ExpectationsWereMet())
+	assert.Nil(t, mock2.ExpectationsWereMet())
+	assert.Nil(t, mock3.ExpectationsWereMet())
+}
+
+func TestHealthDownAndUp(t *testing.T) {
+	testDb1, mock1, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual), sqlmock.MonitorPingsOption(true))
+	assert.Nil(t, err)
+	defer testDb1.Close()
+
+	mock1.ExpectPing()
+	err = InitBy("mysql", []*sql.DB{testDb1})
+	assert.Nil(t, err)
+	// OnEvent may read the health of the nodes
+	var healthy []bool
+	err = StartHealthCheck(HealthConfig{Interval: time.Hour, MaxFailures: 1, OnEvent: func(event NodeEvent) { healthy = append(healthy, Healthy(event.Node)) }})
+	assert.Nil(t, err)
+	defer StopHealthCheck()
+
+	mock1.ExpectPing().WillReturnError(errors.New("ping error"))
+	database.health.check(context.Background())
+	assert.False(t, Healthy(0))
+	assert.Equal(t, Node_Event_Down, (<-Events()).Type)
+
+	mock1.ExpectPing()
+	database.health.check(context.Background())
+	assert.True(t, Healthy(0))
+	assert.Equal(t, Node_Event_Up, (<-Events()).Type)
+	assert.Equal(t, []bool{false, true}, healthy)
+	assert.Nil(t, mock1.ExpectationsWereMet())
+}
@@ -5,8 +5,10 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	ksql "github.com/kovey/db-go/v3"
+	"github.com/kovey/db-go/v3/db"
 )
 
 type ConnectionInterface interface {
@@ -29,7 +31,19 @@ type ConnectionInterface interface {
 	Commit(ctx context.Context) ksql.TxError
 	Transaction(ctx context.Context, keys []any, call func(ctx context.Context, conn ConnectionInterface) error) ksql.TxError
 	TransactionBy(ctx context.Context, keys []any, options *sql.TxOptions, call func(ctx context.Context, conn ConnectionInterface) error) ksql.TxError
+	BeginTo(ctx context.Context, point string) ksql.TxError
+	RollbackTo(ctx context.Context, point string) ksql.TxError
+	CommitTo(ctx context.Context, point string) ksql.TxError
+	Savepoint(ctx context.Context, call func(ctx context.Context, conn ConnectionInterface) error) ksql.TxError
 	ScanRaw(key any, ctx context.Context, raw ksql.ExpressInterface, data ...any) error
+	Stats() []sql.DBStats
+	BreakerStates() []db.BreakerState
+	StmtCacheStats() []db.StmtCacheStats
+	SetMaxOpenConns(n int)
+	SetMaxIdleConns(n int)
+	SetConnMaxLifetime(d time.Duration)
+	SetConnMaxIdleTime(d time.Duration)
+	SetNodePool(node int, conf db.Config) error
 }
 
 type ShardingInterface interface {
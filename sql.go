@@ -67,16 +67,45 @@ const (
 type SqlType string
 
 const (
-	Sql_Type_Insert     SqlType = "INSERT"
-	Sql_Type_Update     SqlType = "UPDATE"
-	Sql_Type_Delete     SqlType = "DELETE"
-	Sql_Type_Drop       SqlType = "DROP"
-	Sql_Type_Alter      SqlType = "ALTER"
-	Sql_Type_Create     SqlType = "CREATE"
-	Sql_Type_Query      SqlType = "QUERY"
-	Sql_Type_Save_Point SqlType = "SAVEPOINT"
-	Sql_Type_Release    SqlType = "RELEASE"
-	Sql_Type_Rollback   SqlType = "ROLLBACK"
+	Sql_Type_Insert      SqlType = "INSERT"
+	Sql_Type_Update      SqlType = "UPDATE"
+	Sql_Type_Delete      SqlType = "DELETE"
+	Sql_Type_Drop        SqlType = "DROP"
+	Sql_Type_Alter       SqlType = "ALTER"
+	Sql_Type_Create      SqlType = "CREATE"
+	Sql_Type_Query       SqlType = "QUERY"
+	Sql_Type_Save_Point  SqlType = "SAVEPOINT"
+	Sql_Type_Release     SqlType = "RELEASE"
+	Sql_Type_Rollback    SqlType = "ROLLBACK"
+	Sql_Type_Replace     SqlType = "REPLACE"
+	Sql_Type_Truncate    SqlType = "TRUNCATE"
+	Sql_Type_Rename      SqlType = "RENAME"
+	Sql_Type_Set         SqlType = "SET"
+	Sql_Type_Call        SqlType = "CALL"
+	Sql_Type_Do          SqlType = "DO"
+	Sql_Type_Show        SqlType = "SHOW"
+	Sql_Type_Explain     SqlType = "EXPLAIN"
+	Sql_Type_Transaction SqlType = "TRANSACTION"
+	Sql_Type_Lock        SqlType = "LOCK"
+	Sql_Type_Use         SqlType = "USE"
+	Sql_Type_Grant       SqlType = "GRANT"
+	Sql_Type_Revoke      SqlType = "REVOKE"
+	Sql_Type_Flush       SqlType = "FLUSH"
+	Sql_Type_Kill        SqlType = "KILL"
+	Sql_Type_Load        SqlType = "LOAD"
+	Sql_Type_Maintenance SqlType = "MAINTENANCE" // ANALYZE, OPTIMIZE, CHECK, CHECKSUM and REPAIR TABLE
+	Sql_Type_Handler     SqlType = "HANDLER"
+	Sql_Type_Xa          SqlType = "XA"
+	Sql_Type_Unknown     SqlType = "UNKNOWN" // leading keyword not recognized, routed as an exec
+)
+
+type MatchMode string
+
+const (
+	Match_Natural_Language           MatchMode = "IN NATURAL LANGUAGE MODE"
+	Match_Natural_Language_Expansion MatchMode = "IN NATURAL LANGUAGE MODE WITH QUERY EXPANSION"
+	Match_Boolean                    MatchMode = "IN BOOLEAN MODE"
+	Match_Query_Expansion            MatchMode = "WITH QUERY EXPANSION"
 )
 
 type Op string
@@ -175,6 +204,41 @@ type ExpressInterface interface {
 	Type() SqlType
 }
 
+// CASE expression, values and results are bound unless they are expresses
+type CaseInterface interface {
+	ExpressInterface
+	When(call func(w WhereInterface), then any) CaseInterface
+	WhenValue(value any, then any) CaseInterface
+	Else(value any) CaseInterface
+	As(as string) CaseInterface
+	Assign(column string) CaseInterface
+}
+
+type FrameBound string
+
+const (
+	Frame_Unbounded_Preceding FrameBound = "UNBOUNDED PRECEDING"
+	Frame_Current_Row         FrameBound = "CURRENT ROW"
+	Frame_Unbounded_Following FrameBound = "UNBOUNDED FOLLOWING"
+)
+
+type WindowInterface interface {
+	ExpressInterface
+	Base(name string) WindowInterface
+	PartitionBy(columns ...string) WindowInterface
+	Order(columns ...string) WindowInterface
+	OrderDesc(columns ...string) WindowInterface
+	Rows(start, end FrameBound) WindowInterface
+	Range(start, end FrameBound) WindowInterface
+}
+
+type WindowFuncInterface interface {
+	ExpressInterface
+	Over(window WindowInterface) WindowFuncInterface
+	OverName(name string) WindowFuncInterface
+	As(as string) WindowFuncInterface
+}
+
 type RowInterface interface {
 	Values() []any
 	Clone() RowInterface
@@ -219,6 +283,19 @@ type WhereInterface interface {
 	Between(column string, begin, end any) WhereInterface
 	NotBetween(column string, begin, end any) WhereInterface
 	AndWhere(call func(o WhereInterface)) WhereInterface
+	Exists(sub QueryInterface) WhereInterface
+	NotExists(sub QueryInterface) WhereInterface
+	WhereBy(column string, op Op, sub QueryInterface) WhereInterface
+	Column(column string, op Op, other string) WhereInterface
+	Row(columns []string, op Op, values []any) WhereInterface
+	RowIn(columns []string, rows [][]any) WhereInterface
+	RowNotIn(columns []string, rows [][]any) WhereInterface
+	RowInBy(columns []string, sub QueryInterface) WhereInterface
+	RowNotInBy(columns []string, sub QueryInterface) WhereInterface
+	JsonContains(column, path string, value any) WhereInterface
+	JsonExtract(column, path string, op Op, value any) WhereInterface
+	MemberOf(column string, value any) WhereInterface
+	Match(columns []string, query string, mode MatchMode) WhereInterface
 	Empty() bool
 	Binds() []any
 	Clone() WhereInterface
@@ -238,6 +315,15 @@ type HavingInterface interface {
 	Between(column string, begin, end any) HavingInterface
 	NotBetween(column string, begin, end any) HavingInterface
 	AndHaving(call func(o HavingInterface)) HavingInterface
+	Exists(sub QueryInterface) HavingInterface
+	NotExists(sub QueryInterface) HavingInterface
+	HavingBy(column string, op Op, sub QueryInterface) HavingInterface
+	Column(column string, op Op, other string) HavingInterface
+	Row(columns []string, op Op, values []any) HavingInterface
+	RowIn(columns []string, rows [][]any) HavingInterface
+	RowNotIn(columns []string, rows [][]any) HavingInterface
+	RowInBy(columns []string, sub QueryInterface) HavingInterface
+	RowNotInBy(columns []string, sub QueryInterface) HavingInterface
 	Empty() bool
 	Binds() []any
 	Clone() HavingInterface
@@ -254,6 +340,9 @@ type JoinInterface interface {
 	Right() JoinInterface
 	Inner() JoinInterface
 	Binds() []any
+	UseIndex(indexes ...string) JoinInterface
+	ForceIndex(indexes ...string) JoinInterface
+	IgnoreIndex(indexes ...string) JoinInterface
 }
 
 type JoinOnInterface interface {
@@ -297,6 +386,18 @@ type UpdateInterface interface {
 	SetColumn(column string, otherColumn string) UpdateInterface
 	Limit(limit int) UpdateInterface
 	IncColumn(column string, data int) UpdateInterface
+	JsonSet(column, path string, value any) UpdateInterface
+	JsonRemove(column string, paths ...string) UpdateInterface
+	With(name string, query QueryInterface, columns ...string) UpdateInterface
+	WithRecursive(name string, query QueryInterface, columns ...string) UpdateInterface
+	UseIndex(indexes ...string) UpdateInterface
+	ForceIndex(indexes ...string) UpdateInterface
+	IgnoreIndex(indexes ...string) UpdateInterface
+	Hint(hints ...string) UpdateInterface
+	HintIndex(table string, indexes ...string) UpdateInterface
+	HintNoIndex(table string, indexes ...string) UpdateInterface
+	HintJoinOrder(tables ...string) UpdateInterface
+	HintSetVar(name string, value any) UpdateInterface
 }
 
 type UpdateMultiInterface interface {
@@ -312,6 +413,8 @@ type UpdateMultiInterface interface {
 	JoinExpress(express ExpressInterface) JoinInterface
 	LeftJoin(table string) JoinInterface
 	RightJoin(table string) JoinInterface
+	With(name string, query QueryInterface, columns ...string) UpdateMultiInterface
+	WithRecursive(name string, query QueryInterface, columns ...string) UpdateMultiInterface
 }
 
 type ColumnFormat string
@@ -499,6 +602,13 @@ type DeleteInterface interface {
 	OrderByDesc(columns ...string) DeleteInterface
 	OrderByAsc(columns ...string) DeleteInterface
 	Limit(limit int) DeleteInterface
+	With(name string, query QueryInterface, columns ...string) DeleteInterface
+	WithRecursive(name string, query QueryInterface, columns ...string) DeleteInterface
+	Hint(hints ...string) DeleteInterface
+	HintIndex(table string, indexes ...string) DeleteInterface
+	HintNoIndex(table string, indexes ...string) DeleteInterface
+	HintJoinOrder(tables ...string) DeleteInterface
+	HintSetVar(name string, value any) DeleteInterface
 }
 
 type DeleteMultiInterface interface {
@@ -513,6 +623,8 @@ type DeleteMultiInterface interface {
 	LeftJoin(table string) JoinInterface
 	RightJoin(table string) JoinInterface
 	Where(WhereInterface) DeleteMultiInterface
+	With(name string, query QueryInterface, columns ...string) DeleteMultiInterface
+	WithRecursive(name string, query QueryInterface, columns ...string) DeleteMultiInterface
 }
 
 type ForInterface interface {
@@ -570,6 +682,8 @@ type QueryInterface interface {
 	LeftJoin(table string) JoinInterface
 	RightJoin(table string) JoinInterface
 	Clone() QueryInterface
+	Keyset(column string, last any, size int) QueryInterface
+	Tables() ([]string, bool)
 	Pagination(page, pageSize int) QueryInterface
 	Distinct() QueryInterface
 	FuncDistinct(fun, column, as string) QueryInterface
@@ -586,10 +700,38 @@ type QueryInterface interface {
 	Partitions(names ...string) QueryInterface
 	GroupWithRollUp() QueryInterface
 	Window(window, as string) QueryInterface
+	WindowBy(name string, spec WindowInterface) QueryInterface
 	OrderWithRollUp() QueryInterface
 	For() ForInterface
 	WhereInCall(column string, call func(query QueryInterface)) QueryInterface
 	WhereNotInCall(column string, call func(query QueryInterface)) QueryInterface
+	Union(query QueryInterface) QueryInterface
+	UnionAll(query QueryInterface) QueryInterface
+	Intersect(query QueryInterface) QueryInterface
+	Except(query QueryInterface) QueryInterface
+	With(name string, query QueryInterface, columns ...string) QueryInterface
+	WithRecursive(name string, query QueryInterface, columns ...string) QueryInterface
+	WhereExists(sub QueryInterface) QueryInterface
+	WhereNotExists(sub QueryInterface) QueryInterface
+	WhereBy(column string, op Op, sub QueryInterface) QueryInterface
+	WhereColumn(column string, op Op, other string) QueryInterface
+	WhereRow(columns []string, op Op, values []any) QueryInterface
+	WhereRowIn(columns []string, rows [][]any) QueryInterface
+	WhereRowInBy(columns []string, sub QueryInterface) QueryInterface
+	HavingBy(column string, op Op, sub QueryInterface) QueryInterface
+	OrderExpress(expresses ...ExpressInterface) QueryInterface
+	OrderDescExpress(expresses ...ExpressInterface) QueryInterface
+	WhereMatch(columns []string, query string, mode MatchMode) QueryInterface
+	ColumnMatch(columns []string, query string, mode MatchMode, as string) QueryInterface
+	Timeout(d time.Duration) QueryInterface
+	UseIndex(indexes ...string) QueryInterface
+	ForceIndex(indexes ...string) QueryInterface
+	IgnoreIndex(indexes ...string) QueryInterface
+	Hint(hints ...string) QueryInterface
+	HintIndex(table string, indexes ...string) QueryInterface
+	HintNoIndex(table string, indexes ...string) QueryInterface
+	HintJoinOrder(tables ...string) QueryInterface
+	HintSetVar(name string, value any) QueryInterface
 }
 
 type CreateTableInterface interface {
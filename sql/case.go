@@ -0,0 +1,136 @@
+package sql
+
+import (
+	"strings"
+
+	ksql "github.com/kovey/db-go/v3"
+	"github.com/kovey/db-go/v3/sql/operator"
+)
+
+type caseWhen struct {
+	where *Where
+	value any
+	then  any
+}
+
+type Case struct {
+	*base
+	column    string
+	whens     []*caseWhen
+	elseValue any
+	hasElse   bool
+	as        string
+	assign    string
+}
+
+// searched case: CASE WHEN condition THEN result ... END
+func NewCase() *Case {
+	c := &Case{base: newBase()}
+	c.opChain.Append(c._assign, c._keyword, c._whens, c._else, c._end)
+	return c
+}
+
+// simple case: CASE column WHEN value THEN result ... END
+func NewCaseOf(column string) *Case {
+	c := NewCase()
+	c.column = column
+	return c
+}
+
+func (c *Case) _value(value any, builder *strings.Builder) {
+	if expr, ok := value.(ksql.ExpressInterface); ok {
+		builder.WriteString(expr.Statement())
+		c.binds = append(c.binds, expr.Binds()...)
+		return
+	}
+
+	builder.WriteString("?")
+	c.binds = append(c.binds, value)
+}
+
+func (c *Case) _assign(builder *strings.Builder) {
+	if c.assign == "" {
+		return
+	}
+
+	operator.Column(c.assign, builder)
+	builder.WriteString(" = ")
+}
+
+func (c *Case) _keyword(builder *strings.Builder) {
+	builder.WriteString("CASE")
+	operator.BuildColumnString(c.column, builder)
+}
+
+func (c *Case) _whens(builder *strings.Builder) {
+	for _, when := range c.whens {
+		builder.WriteString(" WHEN ")
+		if when.where != nil {
+			when.where.Build(builder)
+			c.binds = append(c.binds, when.where.Binds()...)
+		} else {
+			c._value(when.value, builder)
+		}
+
+		builder.WriteString(" THEN ")
+		c._value(when.then, builder)
+	}
+}
+
+func (c *Case) _else(builder *strings.Builder) {
+	if !c.hasElse {
+		return
+	}
+
+	builder.WriteString(" ELSE ")
+	c._value(c.elseValue, builder)
+}
+
+func (c *Case) _end(builder *strings.Builder) {
+	builder.WriteString(" END")
+	if c.as != "" {
+		builder.WriteString(" AS")
+		operator.BuildColumnString(c.as, builder)
+	}
+}
+
+func (c *Case) When(call func(w ksql.WhereInterface), then any) ksql.CaseInterface {
+	w := NewWhere()
+	w.onlyBody = true
+	call(w)
+	c.whens = append(c.whens, &caseWhen{where: w, then: then})
+	return c
+}
+
+func (c *Case) WhenValue(value any, then any) ksql.CaseInterface {
+	c.whens = append(c.whens, &caseWhen{value: value, then: then})
+	return c
+}
+
+func (c *Case) Else(value any) ksql.CaseInterface {
+	c.elseValue = value
+	c.hasElse = true
+	return c
+}
+
+func (c *Case) As(as string) ksql.CaseInterface {
+	c.as = as
+	return c
+}
+
+func (c *Case) Assign(column string) ksql.CaseInterface {
+	c.assign = column
+	return c
+}
+
+func (c *Case) Statement() string {
+	return c.Prepare()
+}
+
+func (c *Case) IsExec() bool {
+	return false
+}
+
+func (c *Case) Type() ksql.SqlType {
+	return ksql.Sql_Type_Query
+}
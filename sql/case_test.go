@@ -0,0 +1,35 @@
+package sql
+
+import (
+	"testing"
+
+	ksql "github.com/kovey/db-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCaseSearched(t *testing.T) {
+	c := NewCase().When(func(w ksql.WhereInterface) {
+		w.Where("score", ksql.Ge, 90)
+	}, "A").When(func(w ksql.WhereInterface) {
+		w.Between("score", 60, 89).IsNotNull("exam_id")
+	}, "B").Else("C").As("grade")
+
+	assert.Equal(t, "CASE WHEN `score` >= ? THEN ? WHEN `score` BETWEEN ? AND ? AND `exam_id` IS NOT NULL THEN ? ELSE ? END AS `grade`", c.Statement())
+	assert.Equal(t, []any{90, "A", 60, 89, "B", "C"}, c.Binds())
+	assert.False(t, c.IsExec())
+	assert.Equal(t, ksql.Sql_Type_Query, c.Type())
+
+	q := NewQuery().Table("student").Columns("name").ColumnsExpress(c).Where("class", ksql.Eq, 3)
+	q.OrderDescExpress(NewCaseOf("status").WhenValue(1, 0).Else(1)).Order("id")
+	assert.Equal(t, "SELECT `name`, CASE WHEN `score` >= ? THEN ? WHEN `score` BETWEEN ? AND ? AND `exam_id` IS NOT NULL THEN ? ELSE ? END AS `grade` FROM `student` WHERE `class` = ? ORDER BY CASE `status` WHEN ? THEN ? ELSE ? END DESC, `id` ASC", q.Prepare())
+	assert.Equal(t, []any{90, "A", 60, 89, "B", "C", 3, 1, 0, 1}, q.Binds())
+}
+
+func TestCaseSimple(t *testing.T) {
+	c := NewCaseOf("id").WhenValue(1, "kovey").WhenValue(2, Raw("CONCAT(`name`, ?)", "_2")).Else(Raw("`name`")).Assign("name")
+	w := NewWhere()
+	w.In("id", []any{1, 2})
+	u := NewUpdate().Table("user").SetExpress(c).Set("age", 18).Where(w)
+	assert.Equal(t, "UPDATE `user` SET `name` = CASE `id` WHEN ? THEN ? WHEN ? THEN CONCAT(`name`, ?) ELSE `name` END, `age` = ? WHERE `id` IN (?, ?)", u.Prepare())
+	assert.Equal(t, []any{1, "kovey", 2, "_2", 18, 1, 2}, u.Binds())
+}
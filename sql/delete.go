@@ -19,16 +19,28 @@ type Delete struct {
 	order       *orderInfo
 	limit       string
 	table       string
+	with        *withInfo
+	hints       *optimizerHints
 }
 
 func NewDelete() *Delete {
-	d := &Delete{base: newBase(), order: &orderInfo{}}
-	d.opChain.Append(d._keyword, d._name, d._partition, d._where, d._order, d._limit)
+	d := &Delete{base: newBase(), order: &orderInfo{}, with: &withInfo{}, hints: &optimizerHints{}}
+	d.opChain.Append(d._with, d._keyword, d._name, d._partition, d._where, d._order, d._limit)
 	return d
 }
 
+func (d *Delete) _with(builder *strings.Builder) {
+	if d.with.Empty() {
+		return
+	}
+
+	d.with.Build(builder)
+	d.binds = append(d.binds, d.with.Binds()...)
+}
+
 func (d *Delete) _keyword(builder *strings.Builder) {
 	builder.WriteString("DELETE")
+	d.hints.Build(builder)
 	operator.BuildPureString(d.lowPriority, builder)
 	operator.BuildPureString(d.quick, builder)
 	operator.BuildPureString(d.ignore, builder)
@@ -137,3 +149,41 @@ func (d *Delete) Limit(limit int) ksql.DeleteInterface {
 	d.limit = strconv.Itoa(limit)
 	return d
 }
+
+func (d *Delete) With(name string, query ksql.QueryInterface, columns ...string) ksql.DeleteInterface {
+	d.with.Append(&cte{name: name, query: query, columns: columns}, false)
+	return d
+}
+
+func (d *Delete) WithRecursive(name string, query ksql.QueryInterface, columns ...string) ksql.DeleteInterface {
+	d.with.Append(&cte{name: name, query: query, columns: columns}, true)
+	return d
+}
+
+// single table DELETE has no index hint syntax, the INDEX and NO_INDEX optimizer hints pin its plan
+func (d *Delete) Hint(hints ...string) ksql.DeleteInterface {
+	for _, hint := range hints {
+		d.hints.Append(hint)
+	}
+	return d
+}
+
+func (d *Delete) HintIndex(table string, indexes ...string) ksql.DeleteInterface {
+	d.hints.Append(tableHint("INDEX", table, indexes))
+	return d
+}
+
+func (d *Delete) HintNoIndex(table string, indexes ...string) ksql.DeleteInterface {
+	d.hints.Append(tableHint("NO_INDEX", table, indexes))
+	return d
+}
+
+func (d *Delete) HintJoinOrder(tables ...string) ksql.DeleteInterface {
+	d.hints.Append(joinOrderHint(tables))
+	return d
+}
+
+func (d *Delete) HintSetVar(name string, value any) ksql.DeleteInterface {
+	d.hints.Append(setVarHint(name, value))
+	return d
+}
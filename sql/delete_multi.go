@@ -20,14 +20,24 @@ type DeleteMulti struct {
 	ignore      string
 	tables      []*tableAs
 	joins       []ksql.JoinInterface
+	with        *withInfo
 }
 
 func NewDeleteMulti() *DeleteMulti {
-	d := &DeleteMulti{base: newBase()}
-	d.opChain.Append(d._keyword, d._name, d._reference, d._where)
+	d := &DeleteMulti{base: newBase(), with: &withInfo{}}
+	d.opChain.Append(d._with, d._keyword, d._name, d._reference, d._where)
 	return d
 }
 
+func (d *DeleteMulti) _with(builder *strings.Builder) {
+	if d.with.Empty() {
+		return
+	}
+
+	d.with.Build(builder)
+	d.binds = append(d.binds, d.with.Binds()...)
+}
+
 func (d *DeleteMulti) _keyword(builder *strings.Builder) {
 	builder.WriteString("DELETE")
 	operator.BuildPureString(d.lowPriority, builder)
@@ -127,3 +137,13 @@ func (d *DeleteMulti) LeftJoin(table string) ksql.JoinInterface {
 func (d *DeleteMulti) RightJoin(table string) ksql.JoinInterface {
 	return d._join(table).Right()
 }
+
+func (d *DeleteMulti) With(name string, query ksql.QueryInterface, columns ...string) ksql.DeleteMultiInterface {
+	d.with.Append(&cte{name: name, query: query, columns: columns}, false)
+	return d
+}
+
+func (d *DeleteMulti) WithRecursive(name string, query ksql.QueryInterface, columns ...string) ksql.DeleteMultiInterface {
+	d.with.Append(&cte{name: name, query: query, columns: columns}, true)
+	return d
+}
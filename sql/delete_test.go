@@ -16,3 +16,20 @@ func TestDetele(t *testing.T) {
 	assert.Equal(t, []any{1}, d.Binds())
 	assert.Equal(t, "DELETE FROM `user` WHERE `id` = ?", d.Prepare())
 }
+
+func TestDeleteWith(t *testing.T) {
+	expired := NewQuery().Table("session").Columns("id").Where("expire", "<", 100)
+	w := NewWhere()
+	w.InBy("id", NewQuery().Table("expired").Columns("id"))
+	d := NewDelete().With("expired", expired).Table("session").Where(w)
+
+	assert.Equal(t, "WITH `expired` AS (SELECT `id` FROM `session` WHERE `expire` < ?) DELETE FROM `session` WHERE `id` IN (SELECT `id` FROM `expired`)", d.Prepare())
+	assert.Equal(t, []any{100}, d.Binds())
+}
+
+func TestDeleteHints(t *testing.T) {
+	d := NewDelete()
+	d.Table("user").Where(NewWhere().Where("group_id", "=", 1)).HintIndex("user", "idx_group_id").Quick()
+	assert.Equal(t, "DELETE /*+ INDEX(`user` `idx_group_id`) */ QUICK FROM `user` WHERE `group_id` = ?", d.Prepare())
+	assert.Equal(t, []any{1}, d.Binds())
+}
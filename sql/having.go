@@ -23,14 +23,28 @@ func NewHaving() *Having {
 	return h
 }
 
+// Clone copies the groups too, a group is built only once
 func (w *Having) Clone() ksql.HavingInterface {
 	o := NewHaving()
-	o.ops = w.ops
-	o.orWheres = w.orWheres
-	o.andWheres = w.andWheres
+	o.ops = append([]*whereOp(nil), w.ops...)
+	o.onlyBody = w.onlyBody
+	o.orWheres = cloneHavings(w.orWheres)
+	o.andWheres = cloneHavings(w.andWheres)
 	return o
 }
 
+func cloneHavings(havings []*Having) []*Having {
+	if havings == nil {
+		return nil
+	}
+
+	clones := make([]*Having, len(havings))
+	for index, having := range havings {
+		clones[index] = having.Clone().(*Having)
+	}
+	return clones
+}
+
 func (w *Having) _keyword(builder *strings.Builder) {
 	if w.onlyBody {
 		return
@@ -79,8 +93,8 @@ func (w *Having) _where(prefix, op string, wheres []*Having, builder *strings.Bu
 		return
 	}
 
-	builder.WriteString(" ")
 	if prefix != "" {
+		builder.WriteString(" ")
 		builder.WriteString(prefix)
 	}
 
@@ -90,7 +104,11 @@ func (w *Having) _where(prefix, op string, wheres []*Having, builder *strings.Bu
 			builder.WriteString(op)
 		}
 
-		builder.WriteString(" (")
+		// a group body starts without space
+		if index > 0 || prefix != "" || !w.onlyBody {
+			builder.WriteString(" ")
+		}
+		builder.WriteString("(")
 		where.Build(builder)
 		builder.WriteString(")")
 		w.binds = append(w.binds, where.Binds()...)
@@ -192,3 +210,48 @@ func (w *Having) NotBetween(column string, begin, end any) ksql.HavingInterface
 func (w *Having) Empty() bool {
 	return len(w.ops) == 0 && len(w.andWheres) == 0 && len(w.orWheres) == 0
 }
+
+func (w *Having) Exists(sub ksql.QueryInterface) ksql.HavingInterface {
+	w.ops = append(w.ops, existsOp("EXISTS", sub))
+	return w
+}
+
+func (w *Having) NotExists(sub ksql.QueryInterface) ksql.HavingInterface {
+	w.ops = append(w.ops, existsOp("NOT EXISTS", sub))
+	return w
+}
+
+func (w *Having) HavingBy(column string, op ksql.Op, sub ksql.QueryInterface) ksql.HavingInterface {
+	w.ops = append(w.ops, subOp(column, op, sub))
+	return w
+}
+
+func (w *Having) Column(column string, op ksql.Op, other string) ksql.HavingInterface {
+	w.ops = append(w.ops, columnOp(column, op, other))
+	return w
+}
+
+func (w *Having) Row(columns []string, op ksql.Op, values []any) ksql.HavingInterface {
+	w.ops = append(w.ops, rowOp(columns, op, values))
+	return w
+}
+
+func (w *Having) RowIn(columns []string, rows [][]any) ksql.HavingInterface {
+	w.ops = append(w.ops, rowInOp(columns, "IN", rows))
+	return w
+}
+
+func (w *Having) RowNotIn(columns []string, rows [][]any) ksql.HavingInterface {
+	w.ops = append(w.ops, rowInOp(columns, "NOT IN", rows))
+	return w
+}
+
+func (w *Having) RowInBy(columns []string, sub ksql.QueryInterface) ksql.HavingInterface {
+	w.ops = append(w.ops, rowInByOp(columns, "IN", sub))
+	return w
+}
+
+func (w *Having) RowNotInBy(columns []string, sub ksql.QueryInterface) ksql.HavingInterface {
+	w.ops = append(w.ops, rowInByOp(columns, "NOT IN", sub))
+	return w
+}
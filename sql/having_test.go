@@ -24,3 +24,15 @@ func TestHaving(t *testing.T) {
 	assert.Equal(t, []any{10, 20, "kovey", 100, 1, 2, 3, 1, 100, 200, 123, 45, 1, 100, 200, "%kk%"}, h.Binds())
 	assert.Equal(t, "HAVING `a`.`id` BETWEEN ? AND ? AND b.name = ? AND `a`.`age` > ? AND `a`.`sex` IN (?, ?, ?) AND `b`.`status` IN (SELECT `user_id` FROM `user` WHERE `status` <> ?) AND `a`.`mail` IS NOT NULL AND `b`.`avatar` IS NULL AND `b`.`num` NOT BETWEEN ? AND ? AND `c`.`id` NOT IN (?, ?) AND `c`.`test` NOT IN (SELECT `user_id` FROM `user` WHERE `status` <> ?) OR (`d`.`info` BETWEEN ? AND ? AND `d`.`other` like ?)", builder.String())
 }
+
+func TestHavingSubquery(t *testing.T) {
+	avg := NewQuery().Table("orders").Func("AVG", "amount", "")
+	h := NewHaving()
+	h.HavingBy("total", ksql.Gt, avg).Column("total", ksql.Ge, "min_total").Row([]string{"a", "b"}, ksql.Eq, []any{1, 2})
+	h.Exists(NewQuery().Table("vip").Columns("id").WhereColumn("vip.user_id", ksql.Eq, "user_id"))
+
+	var builder strings.Builder
+	h.Build(&builder)
+	assert.Equal(t, "HAVING `total` > (SELECT AVG(`amount`) FROM `orders`) AND `total` >= `min_total` AND (`a`, `b`) = (?, ?) AND EXISTS (SELECT `id` FROM `vip` WHERE `vip`.`user_id` = `user_id`)", builder.String())
+	assert.Equal(t, []any{1, 2}, h.Binds())
+}
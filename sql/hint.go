@@ -0,0 +1,138 @@
+package sql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kovey/db-go/v3/sql/operator"
+)
+
+type indexHint struct {
+	typ     string
+	indexes []string
+}
+
+// index hints of a table reference: USE INDEX (...) FORCE INDEX (...) IGNORE INDEX (...)
+type indexHints struct {
+	data []*indexHint
+}
+
+func (i *indexHints) Empty() bool {
+	return i == nil || len(i.data) == 0
+}
+
+func (i *indexHints) Append(typ string, indexes []string) {
+	i.data = append(i.data, &indexHint{typ: typ, indexes: indexes})
+}
+
+func (i *indexHints) Clone() *indexHints {
+	c := &indexHints{data: make([]*indexHint, 0, len(i.data))}
+	for _, hint := range i.data {
+		c.data = append(c.data, &indexHint{typ: hint.typ, indexes: append([]string(nil), hint.indexes...)})
+	}
+
+	return c
+}
+
+func (i *indexHints) Build(builder *strings.Builder) {
+	if i.Empty() {
+		return
+	}
+
+	for _, hint := range i.data {
+		builder.WriteString(" ")
+		builder.WriteString(hint.typ)
+		builder.WriteString(" INDEX (")
+		for index, name := range hint.indexes {
+			if index > 0 {
+				builder.WriteString(", ")
+			}
+			operator.Backtick(name, builder)
+		}
+		builder.WriteString(")")
+	}
+}
+
+// optimizer hints comment right after the SELECT, UPDATE or DELETE keyword: /*+ ... */
+type optimizerHints struct {
+	maxExecutionTime int64
+	items            []string
+}
+
+func (o *optimizerHints) Empty() bool {
+	return o.maxExecutionTime <= 0 && len(o.items) == 0
+}
+
+func (o *optimizerHints) Append(hint string) {
+	o.items = append(o.items, hint)
+}
+
+func (o *optimizerHints) Clone() *optimizerHints {
+	return &optimizerHints{maxExecutionTime: o.maxExecutionTime, items: append([]string(nil), o.items...)}
+}
+
+func (o *optimizerHints) Build(builder *strings.Builder) {
+	if o.Empty() {
+		return
+	}
+
+	builder.WriteString(" /*+")
+	if o.maxExecutionTime > 0 {
+		builder.WriteString(" MAX_EXECUTION_TIME(")
+		builder.WriteString(strconv.FormatInt(o.maxExecutionTime, 10))
+		builder.WriteString(")")
+	}
+
+	for _, hint := range o.items {
+		builder.WriteString(" ")
+		builder.WriteString(hint)
+	}
+	builder.WriteString(" */")
+}
+
+// INDEX(`t` `a`, `b`)
+func tableHint(name, table string, indexes []string) string {
+	var builder strings.Builder
+	builder.WriteString(name)
+	builder.WriteString("(")
+	operator.Backtick(table, &builder)
+	for index, name := range indexes {
+		if index > 0 {
+			builder.WriteString(",")
+		}
+		builder.WriteString(" ")
+		operator.Backtick(name, &builder)
+	}
+	builder.WriteString(")")
+	return builder.String()
+}
+
+// JOIN_ORDER(`a`, `b`)
+func joinOrderHint(tables []string) string {
+	var builder strings.Builder
+	builder.WriteString("JOIN_ORDER(")
+	for index, table := range tables {
+		if index > 0 {
+			builder.WriteString(", ")
+		}
+		operator.Backtick(table, &builder)
+	}
+	builder.WriteString(")")
+	return builder.String()
+}
+
+// SET_VAR(name = value), strings are quoted
+func setVarHint(name string, value any) string {
+	var builder strings.Builder
+	builder.WriteString("SET_VAR(")
+	builder.WriteString(name)
+	builder.WriteString(" = ")
+	if str, ok := value.(string); ok {
+		operator.Quote(strings.ReplaceAll(str, "'", "''"), &builder)
+	} else {
+		builder.WriteString(fmt.Sprint(value))
+	}
+	builder.WriteString(")")
+	return builder.String()
+}
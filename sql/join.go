@@ -67,10 +67,11 @@ type Join struct {
 	expr      ksql.ExpressInterface
 	opChain   *operator.Chain
 	binds     []any
+	hints     *indexHints
 }
 
 func NewJoin() *Join {
-	j := &Join{opChain: operator.NewChain(), ons: &JoinOn{}}
+	j := &Join{opChain: operator.NewChain(), ons: &JoinOn{}, hints: &indexHints{}}
 	j.opChain.Append(j._express, j._join, j._on)
 	return j
 }
@@ -99,6 +100,10 @@ func (j *Join) _on(builder *strings.Builder) {
 		operator.BuildColumnString(j.as, builder)
 	}
 
+	if !j.isExpress {
+		j.hints.Build(builder)
+	}
+
 	if !j.ons.Empty() || len(j.orOns) > 0 {
 		builder.WriteString(" ON ")
 		canOr := false
@@ -203,3 +208,24 @@ func (j *Join) Express(ex ksql.ExpressInterface) ksql.JoinInterface {
 func (j *Join) Binds() []any {
 	return j.binds
 }
+
+func (j *Join) _indexHint(typ string, indexes []string) ksql.JoinInterface {
+	if j.isExpress {
+		return j
+	}
+
+	j.hints.Append(typ, indexes)
+	return j
+}
+
+func (j *Join) UseIndex(indexes ...string) ksql.JoinInterface {
+	return j._indexHint("USE", indexes)
+}
+
+func (j *Join) ForceIndex(indexes ...string) ksql.JoinInterface {
+	return j._indexHint("FORCE", indexes)
+}
+
+func (j *Join) IgnoreIndex(indexes ...string) ksql.JoinInterface {
+	return j._indexHint("IGNORE", indexes)
+}
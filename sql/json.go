@@ -0,0 +1,107 @@
+package sql
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	ksql "github.com/kovey/db-go/v3"
+	"github.com/kovey/db-go/v3/sql/operator"
+)
+
+// jsonError is bound in place of a value json can not encode, the driver reports err when it converts the bind
+type jsonError struct {
+	err error
+}
+
+func (j jsonError) Value() (driver.Value, error) {
+	return nil, j.err
+}
+
+func _jsonDoc(value any) any {
+	buf, err := json.Marshal(value)
+	if err != nil {
+		return jsonError{err: fmt.Errorf("json value %v: %w", value, err)}
+	}
+
+	return string(buf)
+}
+
+// placeholder of value, scalars are bound as is, other values as json document
+func _jsonValue(value any, builder *strings.Builder, binds []any) []any {
+	switch tmp := value.(type) {
+	case ksql.ExpressInterface:
+		builder.WriteString(tmp.Statement())
+		return append(binds, tmp.Binds()...)
+	case nil, string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		builder.WriteString("?")
+		return append(binds, value)
+	default:
+		builder.WriteString("CAST(? AS JSON)")
+		return append(binds, _jsonDoc(value))
+	}
+}
+
+func jsonContains(column, path string, value any) ksql.ExpressInterface {
+	var builder strings.Builder
+	builder.WriteString("JSON_CONTAINS(")
+	operator.Column(column, &builder)
+	builder.WriteString(", ?")
+	binds := []any{_jsonDoc(value)}
+	if path != "" {
+		builder.WriteString(", ?")
+		binds = append(binds, path)
+	}
+	builder.WriteString(")")
+	return Raw(builder.String(), binds...)
+}
+
+func jsonExtract(column, path string, op ksql.Op, value any) ksql.ExpressInterface {
+	if !ksql.SupportOp(op) {
+		panic(fmt.Sprintf("op %s not support", op))
+	}
+
+	var builder strings.Builder
+	builder.WriteString("JSON_EXTRACT(")
+	operator.Column(column, &builder)
+	builder.WriteString(", ?) ")
+	builder.WriteString(string(op))
+	builder.WriteString(" ")
+	binds := _jsonValue(value, &builder, []any{path})
+	return Raw(builder.String(), binds...)
+}
+
+func memberOf(column string, value any) ksql.ExpressInterface {
+	var builder strings.Builder
+	binds := _jsonValue(value, &builder, nil)
+	builder.WriteString(" MEMBER OF(")
+	operator.Column(column, &builder)
+	builder.WriteString(")")
+	return Raw(builder.String(), binds...)
+}
+
+func jsonSet(column, path string, value any) ksql.ExpressInterface {
+	var builder strings.Builder
+	operator.Column(column, &builder)
+	builder.WriteString(" = JSON_SET(")
+	operator.Column(column, &builder)
+	builder.WriteString(", ?, ")
+	binds := _jsonValue(value, &builder, []any{path})
+	builder.WriteString(")")
+	return Raw(builder.String(), binds...)
+}
+
+func jsonRemove(column string, paths ...string) ksql.ExpressInterface {
+	var builder strings.Builder
+	operator.Column(column, &builder)
+	builder.WriteString(" = JSON_REMOVE(")
+	operator.Column(column, &builder)
+	binds := make([]any, len(paths))
+	for index, path := range paths {
+		builder.WriteString(", ?")
+		binds[index] = path
+	}
+	builder.WriteString(")")
+	return Raw(builder.String(), binds...)
+}
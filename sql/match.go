@@ -0,0 +1,24 @@
+package sql
+
+import (
+	"strings"
+
+	ksql "github.com/kovey/db-go/v3"
+	"github.com/kovey/db-go/v3/sql/operator"
+)
+
+// full-text search: MATCH (columns) AGAINST (? mode), empty mode uses natural language mode
+func Match(columns []string, query string, mode ksql.MatchMode) ksql.ExpressInterface {
+	var builder strings.Builder
+	builder.WriteString("MATCH (")
+	for index, column := range columns {
+		if index > 0 {
+			builder.WriteString(", ")
+		}
+		operator.Column(column, &builder)
+	}
+	builder.WriteString(") AGAINST (?")
+	operator.BuildPureString(string(mode), &builder)
+	builder.WriteString(")")
+	return Raw(builder.String(), query)
+}
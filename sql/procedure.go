@@ -247,7 +247,8 @@ func (p *Procedure) SqlSecurity(security ksql.SqlSecurity) ksql.ProcedureInterfa
 }
 
 func (p *Procedure) RoutineBody(sql ksql.ExpressInterface) ksql.ProcedureInterface {
-	if !p.isCreate || sql.IsExec() {
+	// bodies such as RETURN ... or IF ... start with a keyword the statement lexer does not know
+	if !p.isCreate || (sql.IsExec() && sql.Type() != ksql.Sql_Type_Unknown) {
 		return p
 	}
 
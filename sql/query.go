@@ -2,6 +2,7 @@ package sql
 
 import (
 	"strings"
+	"time"
 
 	ksql "github.com/kovey/db-go/v3"
 	"github.com/kovey/db-go/v3/sql/operator"
@@ -95,6 +96,15 @@ func (o *orderInfo) Append(column *orderMeta) {
 	o.columns = append(o.columns, column)
 }
 
+func (o *orderInfo) Binds() []any {
+	var binds []any
+	for _, column := range o.columns {
+		binds = append(binds, column.column.Binds()...)
+	}
+
+	return binds
+}
+
 func (o *orderInfo) Empty() bool {
 	return len(o.columns) == 0
 }
@@ -168,10 +178,18 @@ func (t *tableInfo) Binds() []any {
 type window struct {
 	name string
 	as   string
+	spec ksql.WindowInterface
 }
 
 func (w *window) Build(builder *strings.Builder) {
 	operator.BuildColumnString(w.name, builder)
+	if w.spec != nil {
+		builder.WriteString(" AS (")
+		builder.WriteString(w.spec.Statement())
+		builder.WriteString(")")
+		return
+	}
+
 	builder.WriteString(" AS")
 	operator.BuildColumnString(w.as, builder)
 }
@@ -198,6 +216,19 @@ func (w *windows) Build(builder *strings.Builder) {
 	}
 }
 
+type compound struct {
+	typ   string
+	query ksql.QueryInterface
+}
+
+func (c *compound) Build(builder *strings.Builder) {
+	builder.WriteString(" ")
+	builder.WriteString(c.typ)
+	builder.WriteString(" (")
+	builder.WriteString(c.query.Prepare())
+	builder.WriteString(")")
+}
+
 type limitInfo struct {
 	hasLimit  bool
 	limit     int
@@ -251,19 +282,37 @@ type Query struct {
 	sqlNoCache       string
 	sqlCalcFoundRows string
 	windows          *windows
+	compounds        []*compound
+	with             *withInfo
+	hints            *optimizerHints
+	indexHints       *indexHints
 }
 
 func NewQuery() *Query {
 	q := &Query{
 		base: newBase(), where: NewWhere(), having: NewHaving(), sharding: ksql.Sharding_None, columns: &columnInfos{}, group: newGroupInfo(), order: &orderInfo{},
-		forSql: &For{}, table: &tableInfo{}, windows: &windows{}, limitInfo: &limitInfo{},
+		forSql: &For{}, table: &tableInfo{}, windows: &windows{}, limitInfo: &limitInfo{}, with: &withInfo{},
+		hints: &optimizerHints{}, indexHints: &indexHints{},
 	}
-	q.opChain.Append(q._keyword, q._columns, q._into, q._from, q._joinInfo, q._partition, q._where, q._group, q._having, q._window, q._order, q._limit, q._for)
+	q.opChain.Append(q._with, q._keyword, q._columns, q._into, q._from, q._joinInfo, q._partition, q._where, q._group, q._having, q._window, q._compounds, q._order, q._limit, q._for)
 	return q
 }
 
+func (o *Query) _with(builder *strings.Builder) {
+	if o.with.Empty() {
+		return
+	}
+
+	o.with.Build(builder)
+	o.binds = append(o.binds, o.with.Binds()...)
+}
+
 func (o *Query) _keyword(builder *strings.Builder) {
+	if len(o.compounds) > 0 {
+		builder.WriteString("(")
+	}
 	builder.WriteString("SELECT")
+	o.hints.Build(builder)
 	operator.BuildPureString(o.modifer, builder)
 	operator.BuildPureString(o.highPriority, builder)
 	operator.BuildPureString(o.straightJoin, builder)
@@ -303,6 +352,7 @@ func (o *Query) _into(builder *strings.Builder) {
 func (o *Query) _from(builder *strings.Builder) {
 	builder.WriteString(" FROM")
 	o.table.Build(builder)
+	o.indexHints.Build(builder)
 	o.binds = append(o.binds, o.table.Binds()...)
 }
 
@@ -371,12 +421,25 @@ func (o *Query) _window(builder *strings.Builder) {
 	o.windows.Build(builder)
 }
 
+func (o *Query) _compounds(builder *strings.Builder) {
+	if len(o.compounds) == 0 {
+		return
+	}
+
+	builder.WriteString(")")
+	for _, c := range o.compounds {
+		c.Build(builder)
+		o.binds = append(o.binds, c.query.Binds()...)
+	}
+}
+
 func (o *Query) _order(builder *strings.Builder) {
 	if o.order.Empty() {
 		return
 	}
 
 	o.order.Build(builder)
+	o.binds = append(o.binds, o.order.Binds()...)
 }
 
 func (o *Query) _limit(builder *strings.Builder) {
@@ -394,18 +457,69 @@ func (o *Query) _for(builder *strings.Builder) {
 	o.forSql.Build(builder)
 }
 
+// Clone copies the query with no columns. A compound query is wrapped as SELECT ... FROM (compound) AS `compound`,
+// its ORDER BY and LIMIT stay inside the derived table so the clone reads the same rows
 func (o *Query) Clone() ksql.QueryInterface {
+	if len(o.compounds) > 0 {
+		order := &orderInfo{columns: append([]*orderMeta(nil), o.order.columns...), with: o.order.with}
+		limit := *o.limitInfo
+		return o._cloneCompound(order, &limit)
+	}
+
 	q := &Query{
 		base: newBase(), where: o.where.Clone(), having: o.having.Clone(),
 		table: o.table, join: o.join, group: o.group, initBinds: o.initBinds, order: o.order, intoVars: o.intoVars, limitInfo: o.limitInfo, modifer: o.modifer,
 		forSql: o.forSql, partitions: o.partitions, highPriority: o.highPriority, straightJoin: o.straightJoin, windows: o.windows, columns: &columnInfos{},
+		compounds: o.compounds, with: o.with, hints: o.hints.Clone(), indexHints: o.indexHints.Clone(),
+	}
+	q.opChain.Append(q._with, q._keyword, q._columns, q._into, q._from, q._joinInfo, q._partition, q._where, q._group, q._having, q._window, q._compounds, q._order, q._limit, q._for)
+	return q
+}
+
+// Keyset clones the query like Clone without its order, limit and offset and reads size rows ordered by column after last,
+// the conditions of the query are grouped in parentheses so column > last narrows every OR group, nil last reads from the start
+func (o *Query) Keyset(column string, last any, size int) ksql.QueryInterface {
+	var q *Query
+	if len(o.compounds) > 0 {
+		q = o._cloneCompound(&orderInfo{}, &limitInfo{}).(*Query)
+	} else {
+		q = o.Clone().(*Query)
 	}
-	q.opChain.Append(q._keyword, q._columns, q._into, q._from, q._joinInfo, q._partition, q._where, q._group, q._having, q._window, q._order, q._limit, q._for)
+	q.order = &orderInfo{}
+	q.limitInfo = &limitInfo{}
+	w := NewWhere()
+	if last != nil {
+		w.Where(column, ksql.Gt, last)
+	}
+
+	if inner, ok := q.where.(*Where); ok && !inner.Empty() {
+		inner.onlyBody = true
+		w.andWheres = append(w.andWheres, inner)
+	}
+
+	q.where = w
+	q.Order(column).Limit(size)
 	return q
 }
 
+// compound query is selected as derived table with order and limit, so the new columns apply to the combined result
+func (o *Query) _cloneCompound(order *orderInfo, limit *limitInfo) ksql.QueryInterface {
+	inner := &Query{
+		base: newBase(), where: o.where.Clone(), having: o.having.Clone(),
+		table: o.table, join: o.join, group: o.group, initBinds: o.initBinds, order: order, limitInfo: limit, modifer: o.modifer,
+		partitions: o.partitions, highPriority: o.highPriority, straightJoin: o.straightJoin, windows: o.windows, columns: &columnInfos{columns: o.columns.columns},
+		compounds: o.compounds, forSql: &For{}, with: o.with, hints: o.hints.Clone(), indexHints: o.indexHints.Clone(),
+	}
+	inner.opChain.Append(inner._with, inner._keyword, inner._columns, inner._into, inner._from, inner._joinInfo, inner._partition, inner._where, inner._group, inner._having, inner._window, inner._compounds, inner._order, inner._limit, inner._for)
+
+	return NewQuery().TableBy(inner, "compound")
+}
+
 func (o *Query) TableBy(operater ksql.QueryInterface, as string) ksql.QueryInterface {
 	o.table.sub = operater
+	if as != "" {
+		o.table.as = as
+	}
 	return o
 }
 
@@ -564,6 +678,20 @@ func (o *Query) OrderDesc(columns ...string) ksql.QueryInterface {
 	return o
 }
 
+func (o *Query) OrderExpress(expresses ...ksql.ExpressInterface) ksql.QueryInterface {
+	for _, express := range expresses {
+		o.order.Append(&orderMeta{column: &columnInfo{expr: express}, typ: "ASC"})
+	}
+	return o
+}
+
+func (o *Query) OrderDescExpress(expresses ...ksql.ExpressInterface) ksql.QueryInterface {
+	for _, express := range expresses {
+		o.order.Append(&orderMeta{column: &columnInfo{expr: express}, typ: "DESC"})
+	}
+	return o
+}
+
 func (o *Query) Group(columns ...string) ksql.QueryInterface {
 	for _, column := range columns {
 		o.group.columns.Append(&columnInfo{column: column})
@@ -680,7 +808,7 @@ func (o *Query) Distinct() ksql.QueryInterface {
 }
 
 func (o *Query) FuncDistinct(fun, column, as string) ksql.QueryInterface {
-	o.columns.Append(&columnInfo{prefix: "DISTINCT", column: column, fun: fun, as: as})
+	o.columns.Append(&columnInfo{isFunc: true, prefix: "DISTINCT", column: column, fun: fun, as: as})
 	return o
 }
 
@@ -744,6 +872,11 @@ func (o *Query) Window(win, as string) ksql.QueryInterface {
 	return o
 }
 
+func (o *Query) WindowBy(name string, spec ksql.WindowInterface) ksql.QueryInterface {
+	o.windows.Append(&window{name: name, spec: spec})
+	return o
+}
+
 func (o *Query) OrderWithRollUp() ksql.QueryInterface {
 	o.order.with = "WITH ROLLUP"
 	return o
@@ -766,3 +899,133 @@ func (o *Query) WhereNotInCall(column string, call func(query ksql.QueryInterfac
 	o.where.NotInBy(column, query)
 	return o
 }
+
+func (o *Query) _compound(typ string, query ksql.QueryInterface) ksql.QueryInterface {
+	o.compounds = append(o.compounds, &compound{typ: typ, query: query})
+	return o
+}
+
+func (o *Query) Union(query ksql.QueryInterface) ksql.QueryInterface {
+	return o._compound("UNION", query)
+}
+
+func (o *Query) UnionAll(query ksql.QueryInterface) ksql.QueryInterface {
+	return o._compound("UNION ALL", query)
+}
+
+func (o *Query) Intersect(query ksql.QueryInterface) ksql.QueryInterface {
+	return o._compound("INTERSECT", query)
+}
+
+func (o *Query) Except(query ksql.QueryInterface) ksql.QueryInterface {
+	return o._compound("EXCEPT", query)
+}
+
+func (o *Query) With(name string, query ksql.QueryInterface, columns ...string) ksql.QueryInterface {
+	o.with.Append(&cte{name: name, query: query, columns: columns}, false)
+	return o
+}
+
+func (o *Query) WithRecursive(name string, query ksql.QueryInterface, columns ...string) ksql.QueryInterface {
+	o.with.Append(&cte{name: name, query: query, columns: columns}, true)
+	return o
+}
+
+func (o *Query) WhereExists(sub ksql.QueryInterface) ksql.QueryInterface {
+	o.where.Exists(sub)
+	return o
+}
+
+func (o *Query) WhereNotExists(sub ksql.QueryInterface) ksql.QueryInterface {
+	o.where.NotExists(sub)
+	return o
+}
+
+func (o *Query) WhereBy(column string, op ksql.Op, sub ksql.QueryInterface) ksql.QueryInterface {
+	o.where.WhereBy(column, op, sub)
+	return o
+}
+
+func (o *Query) WhereColumn(column string, op ksql.Op, other string) ksql.QueryInterface {
+	o.where.Column(column, op, other)
+	return o
+}
+
+func (o *Query) WhereRow(columns []string, op ksql.Op, values []any) ksql.QueryInterface {
+	o.where.Row(columns, op, values)
+	return o
+}
+
+func (o *Query) WhereRowIn(columns []string, rows [][]any) ksql.QueryInterface {
+	o.where.RowIn(columns, rows)
+	return o
+}
+
+func (o *Query) WhereRowInBy(columns []string, sub ksql.QueryInterface) ksql.QueryInterface {
+	o.where.RowInBy(columns, sub)
+	return o
+}
+
+func (o *Query) HavingBy(column string, op ksql.Op, sub ksql.QueryInterface) ksql.QueryInterface {
+	o.having.HavingBy(column, op, sub)
+	return o
+}
+
+func (o *Query) WhereMatch(columns []string, query string, mode ksql.MatchMode) ksql.QueryInterface {
+	o.where.Match(columns, query, mode)
+	return o
+}
+
+func (o *Query) ColumnMatch(columns []string, query string, mode ksql.MatchMode, as string) ksql.QueryInterface {
+	o.columns.Append(&columnInfo{expr: Match(columns, query, mode), as: as})
+	return o
+}
+
+// Timeout limits the execution of the select with the MAX_EXECUTION_TIME optimizer hint, millisecond precision
+func (o *Query) Timeout(d time.Duration) ksql.QueryInterface {
+	o.hints.maxExecutionTime = d.Milliseconds()
+	return o
+}
+
+func (o *Query) UseIndex(indexes ...string) ksql.QueryInterface {
+	o.indexHints.Append("USE", indexes)
+	return o
+}
+
+func (o *Query) ForceIndex(indexes ...string) ksql.QueryInterface {
+	o.indexHints.Append("FORCE", indexes)
+	return o
+}
+
+func (o *Query) IgnoreIndex(indexes ...string) ksql.QueryInterface {
+	o.indexHints.Append("IGNORE", indexes)
+	return o
+}
+
+// Hint appends raw optimizer hints, e.g. BKA(t1) or NO_RANGE_OPTIMIZATION(t3 PRIMARY)
+func (o *Query) Hint(hints ...string) ksql.QueryInterface {
+	for _, hint := range hints {
+		o.hints.Append(hint)
+	}
+	return o
+}
+
+func (o *Query) HintIndex(table string, indexes ...string) ksql.QueryInterface {
+	o.hints.Append(tableHint("INDEX", table, indexes))
+	return o
+}
+
+func (o *Query) HintNoIndex(table string, indexes ...string) ksql.QueryInterface {
+	o.hints.Append(tableHint("NO_INDEX", table, indexes))
+	return o
+}
+
+func (o *Query) HintJoinOrder(tables ...string) ksql.QueryInterface {
+	o.hints.Append(joinOrderHint(tables))
+	return o
+}
+
+func (o *Query) HintSetVar(name string, value any) ksql.QueryInterface {
+	o.hints.Append(setVarHint(name, value))
+	return o
+}
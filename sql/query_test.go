@@ -2,6 +2,7 @@ package sql
 
 import (
 	"testing"
+	"time"
 
 	ksql "github.com/kovey/db-go/v3"
 	"github.com/stretchr/testify/assert"
@@ -39,3 +40,133 @@ func TestQuery(t *testing.T) {
 	assert.Equal(t, []any{1, 0, 0, 0, 0, 10, 20, 1000, 2000, 1000, 1, 2, 3, 100, 1, 4, 5, 5, 5, 10, 100, 100, 200, 1000, 3000, 5000, 10, 0}, q.Binds())
 	assert.Equal(t, "SELECT `nickname` AS `name`, `avatar`, `sex`, `id_card`, username as account, sum(`balance`) AS `balance` FROM `user` AS `u` INNER JOIN `ext` AS `e` ON (`e`.`id` = `u`.`user_id`) JOIN info as i on i.id = u.user_id and i.status = ? LEFT JOIN `account` AS `a` ON (`a`.`id` = `u`.`user_id`) OR (`a`.`balance` > ? AND `a`.`freezen` = ?) RIGHT JOIN `login_info` AS `li` ON (`li`.`user_id` = `u`.`user_id`) OR (`li`.`count` > ? AND `li`.`date` = ?) WHERE `u`.`age` BETWEEN ? AND ? AND `u`.`phone` NOT BETWEEN ? AND ? AND `u`.`id` > ? AND `u`.`status` IN (?, ?, ?) AND `u`.`avatar` IS NOT NULL AND `u`.`id_card` IS NULL AND i.id > ? and i.status = ? AND `i`.`status` NOT IN (?, ?) AND `u`.`game_id` IN (SELECT `id` FROM `game` WHERE `status` = ?) AND `u`.`room_id` NOT IN (SELECT `id` FROM `room` WHERE `status` = ?) OR (`u`.`game_status` = ? AND `u`.`game_other` IS NULL) GROUP BY `user_id`, `sex` HAVING `balance` > ? AND name is not null OR (`li`.`count` BETWEEN ? AND ? AND `li`.`coin` > ? AND `li`.`page` NOT BETWEEN ? AND ?) ORDER BY `u`.`user_id` ASC, `li`.`balance` DESC LIMIT ? OFFSET ? FOR UPDATE", q.Prepare())
 }
+
+func TestQueryUnion(t *testing.T) {
+	q := NewQuery().Table("user").Columns("id", "name").Where("status", "=", 1)
+	q.Union(NewQuery().Table("user_archive").Columns("id", "name").Where("status", "=", 2))
+	q.UnionAll(NewQuery().Table("user_deleted").Columns("id", "name").Where("age", ">", 18).Order("id").Limit(5))
+	q.OrderDesc("id").Limit(10).Offset(20)
+	assert.Equal(t, "(SELECT `id`, `name` FROM `user` WHERE `status` = ?) UNION (SELECT `id`, `name` FROM `user_archive` WHERE `status` = ?) UNION ALL (SELECT `id`, `name` FROM `user_deleted` WHERE `age` > ? ORDER BY `id` ASC LIMIT ?) ORDER BY `id` DESC LIMIT ? OFFSET ?", q.Prepare())
+	assert.Equal(t, []any{1, 2, 18, 5, 10, 20}, q.Binds())
+
+	i := NewQuery().Table("a").Columns("id").Intersect(NewQuery().Table("b").Columns("id")).Except(NewQuery().Table("c").Columns("id"))
+	assert.Equal(t, "(SELECT `id` FROM `a`) INTERSECT (SELECT `id` FROM `b`) EXCEPT (SELECT `id` FROM `c`)", i.Prepare())
+
+	c := q.Clone()
+	c.ColumnsExpress(Raw("COUNT(1) as count"))
+	assert.Equal(t, "SELECT COUNT(1) as count FROM ((SELECT `id`, `name` FROM `user` WHERE `status` = ?) UNION (SELECT `id`, `name` FROM `user_archive` WHERE `status` = ?) UNION ALL (SELECT `id`, `name` FROM `user_deleted` WHERE `age` > ? ORDER BY `id` ASC LIMIT ?) ORDER BY `id` DESC LIMIT ? OFFSET ?) AS `compound`", c.Prepare())
+	assert.Equal(t, []any{1, 2, 18, 5, 10, 20}, c.Binds())
+
+	// the clone keeps the order and limit it was made with
+	q.Order("name").Limit(30)
+	p := q.Clone()
+	p.Columns("id")
+	q.Limit(40)
+	assert.Equal(t, "SELECT `id` FROM ((SELECT `id`, `name` FROM `user` WHERE `status` = ?) UNION (SELECT `id`, `name` FROM `user_archive` WHERE `status` = ?) UNION ALL (SELECT `id`, `name` FROM `user_deleted` WHERE `age` > ? ORDER BY `id` ASC LIMIT ?) ORDER BY `id` DESC, `name` ASC LIMIT ? OFFSET ?) AS `compound`", p.Prepare())
+	assert.Equal(t, []any{1, 2, 18, 5, 30, 20}, p.Binds())
+
+	from := NewQuery().TableBy(NewQuery().Table("a").Columns("id").Union(NewQuery().Table("b").Columns("id")), "u").Columns("u.id")
+	assert.Equal(t, "SELECT `u`.`id` FROM ((SELECT `id` FROM `a`) UNION (SELECT `id` FROM `b`)) AS `u`", from.Prepare())
+
+	ins := NewInsert().Table("user_all").Columns("id").From(NewQuery().Table("a").Columns("id").UnionAll(NewQuery().Table("b").Columns("id").Where("id", ">", 3)))
+	assert.Contains(t, ins.Prepare(), "(SELECT `id` FROM `a`) UNION ALL (SELECT `id` FROM `b` WHERE `id` > ?)")
+	assert.Equal(t, []any{3}, ins.Binds())
+}
+
+func TestQueryWith(t *testing.T) {
+	orders := NewQuery().Table("orders").Columns("user_id").Func("SUM", "amount", "total").Where("status", "=", 1).Group("user_id")
+	q := NewQuery().With("totals", orders).Table("user").As("u").Columns("u.name", "t.total").Where("t.total", ">", 100)
+	q.Join("totals").As("t").On("t.user_id", "=", "u.id")
+	assert.Equal(t, "WITH `totals` AS (SELECT `user_id`, SUM(`amount`) AS `total` FROM `orders` WHERE `status` = ? GROUP BY `user_id`) SELECT `u`.`name`, `t`.`total` FROM `user` AS `u` INNER JOIN `totals` AS `t` ON (`t`.`user_id` = `u`.`id`) WHERE `t`.`total` > ?", q.Prepare())
+	assert.Equal(t, []any{1, 100}, q.Binds())
+
+	anchor := NewQuery().Table("category").Columns("id", "parent_id").Where("id", "=", 5)
+	member := NewQuery().Table("category").As("c").Columns("c.id", "c.parent_id")
+	member.Join("tree").As("t").On("c.parent_id", "=", "t.id")
+	anchor.UnionAll(member)
+	tree := NewQuery().WithRecursive("tree", anchor, "id", "parent_id").Table("tree").Columns("id").Limit(100)
+	assert.Equal(t, "WITH RECURSIVE `tree` (`id`, `parent_id`) AS ((SELECT `id`, `parent_id` FROM `category` WHERE `id` = ?) UNION ALL (SELECT `c`.`id`, `c`.`parent_id` FROM `category` AS `c` INNER JOIN `tree` AS `t` ON (`c`.`parent_id` = `t`.`id`))) SELECT `id` FROM `tree` LIMIT ?", tree.Prepare())
+	assert.Equal(t, []any{5, 100}, tree.Binds())
+
+	c := tree.Clone()
+	c.ColumnsExpress(Raw("COUNT(1) as count"))
+	assert.Equal(t, "WITH RECURSIVE `tree` (`id`, `parent_id`) AS ((SELECT `id`, `parent_id` FROM `category` WHERE `id` = ?) UNION ALL (SELECT `c`.`id`, `c`.`parent_id` FROM `category` AS `c` INNER JOIN `tree` AS `t` ON (`c`.`parent_id` = `t`.`id`))) SELECT COUNT(1) as count FROM `tree` LIMIT ?", c.Prepare())
+	assert.Equal(t, []any{5, 100}, c.Binds())
+}
+
+func TestQueryMatch(t *testing.T) {
+	q := NewQuery().Table("article").Columns("id", "title").ColumnMatch([]string{"title", "body"}, "golang", ksql.Match_Query_Expansion, "score")
+	q.WhereMatch([]string{"title", "body"}, "golang", ksql.Match_Query_Expansion).Where("status", "=", 1).OrderDesc("score").Limit(10)
+	assert.Equal(t, "SELECT `id`, `title`, MATCH (`title`, `body`) AGAINST (? WITH QUERY EXPANSION) AS `score` FROM `article` WHERE MATCH (`title`, `body`) AGAINST (? WITH QUERY EXPANSION) AND `status` = ? ORDER BY `score` DESC LIMIT ?", q.Prepare())
+	assert.Equal(t, []any{"golang", "golang", 1, 10}, q.Binds())
+}
+
+func TestQueryTimeout(t *testing.T) {
+	q := NewQuery().Table("user").Columns("id").Distinct().Where("id", ">", 1).Timeout(1500 * time.Millisecond)
+	assert.Equal(t, "SELECT /*+ MAX_EXECUTION_TIME(1500) */ DISTINCT `id` FROM `user` WHERE `id` > ?", q.Prepare())
+	assert.Equal(t, []any{1}, q.Binds())
+
+	c := q.Clone()
+	c.Func("COUNT", "id", "count")
+	assert.Equal(t, "SELECT /*+ MAX_EXECUTION_TIME(1500) */ DISTINCT COUNT(`id`) AS `count` FROM `user` WHERE `id` > ?", c.Prepare())
+	assert.Equal(t, "SELECT `id` FROM `user`", NewQuery().Table("user").Columns("id").Timeout(0).Prepare())
+}
+
+func TestQueryHints(t *testing.T) {
+	q := NewQuery().Table("order").As("o").Columns("o.id", "u.name").ForceIndex("idx_user_id").IgnoreIndex("idx_status", "idx_create_time")
+	q.Join("user").As("u").UseIndex("PRIMARY").On("u.id", "=", "o.user_id")
+	q.Where("o.status", "=", 1).HintJoinOrder("o", "u").HintIndex("o", "idx_user_id", "idx_status").HintNoIndex("u", "idx_name").HintSetVar("sort_buffer_size", 16777216).HintSetVar("optimizer_switch", "mrr=on").Hint("BKA(u)").Timeout(time.Second)
+	assert.Equal(t, "SELECT /*+ MAX_EXECUTION_TIME(1000) JOIN_ORDER(`o`, `u`) INDEX(`o` `idx_user_id`, `idx_status`) NO_INDEX(`u` `idx_name`) SET_VAR(sort_buffer_size = 16777216) SET_VAR(optimizer_switch = 'mrr=on') BKA(u) */ `o`.`id`, `u`.`name` FROM `order` AS `o` FORCE INDEX (`idx_user_id`) IGNORE INDEX (`idx_status`, `idx_create_time`) INNER JOIN `user` AS `u` USE INDEX (`PRIMARY`) ON (`u`.`id` = `o`.`user_id`) WHERE `o`.`status` = ?", q.Prepare())
+	assert.Equal(t, []any{1}, q.Binds())
+}
+
+func TestQueryCloneHints(t *testing.T) {
+	q := NewQuery().Table("user").Columns("id").UseIndex("idx_age").Hint("BKA(user)").Timeout(time.Second)
+	c := q.Clone()
+	c.Columns("id").ForceIndex("PRIMARY").Hint("NO_BKA(user)").Timeout(2 * time.Second)
+	assert.Equal(t, "SELECT /*+ MAX_EXECUTION_TIME(2000) BKA(user) NO_BKA(user) */ `id` FROM `user` USE INDEX (`idx_age`) FORCE INDEX (`PRIMARY`)", c.Prepare())
+	assert.Equal(t, "SELECT /*+ MAX_EXECUTION_TIME(1000) BKA(user) */ `id` FROM `user` USE INDEX (`idx_age`)", q.Prepare())
+}
+
+func TestQueryFuncDistinct(t *testing.T) {
+	q := NewQuery().Table("order").FuncDistinct("COUNT", "o.user_id", "users")
+	assert.Equal(t, "SELECT COUNT(DISTINCT `o`.`user_id`) AS `users` FROM `order`", q.Prepare())
+}
+
+func TestQueryKeyset(t *testing.T) {
+	q := NewQuery().Table("user").Columns("id").Where("age", ">", 18).OrWhere(func(w ksql.WhereInterface) { w.Where("vip", "=", 1) }).OrderDesc("created").Limit(10).Offset(20)
+	first := q.Keyset("id", nil, 2)
+	first.Columns("id", "name")
+	assert.Equal(t, "SELECT `id`, `name` FROM `user` WHERE (`age` > ? OR (`vip` = ?)) ORDER BY `id` ASC LIMIT ?", first.Prepare())
+	assert.Equal(t, []any{18, 1, 2}, first.Binds())
+
+	next := q.Keyset("id", 5, 2)
+	next.Columns("id", "name")
+	assert.Equal(t, "SELECT `id`, `name` FROM `user` WHERE `id` > ? AND (`age` > ? OR (`vip` = ?)) ORDER BY `id` ASC LIMIT ?", next.Prepare())
+	assert.Equal(t, []any{5, 18, 1, 2}, next.Binds())
+
+	assert.Equal(t, "SELECT `id` FROM `user` WHERE `age` > ? OR (`vip` = ?) ORDER BY `created` DESC LIMIT ? OFFSET ?", q.Prepare())
+	assert.Equal(t, []any{18, 1, 10, 20}, q.Binds())
+}
+
+func TestQueryTables(t *testing.T) {
+	recent := NewQuery().Table("orders").Columns("user_id").Where("created", ">", "2025-01-01")
+	vip := NewQuery().Table("vip").Columns("user_id")
+	q := NewQuery().With("recent", recent).Table("user").As("u").Columns("u.id").WhereInBy("u.id", vip).OrWhere(func(w ksql.WhereInterface) {
+		w.Exists(NewQuery().Table("ban").Columns("id"))
+	}).UnionAll(NewQuery().Table("user_archive").Columns("id"))
+	q.LeftJoin("profile").As("p").On("p.user_id", "=", "u.id")
+	tables, ok := q.Tables()
+	assert.True(t, ok)
+	assert.ElementsMatch(t, []string{"user", "profile", "vip", "ban", "orders", "user_archive"}, tables)
+
+	derived := NewQuery().TableBy(NewQuery().Table("user").Columns("id"), "t").Columns("id")
+	tables, ok = derived.Tables()
+	assert.True(t, ok)
+	assert.Equal(t, []string{"user"}, tables)
+
+	raw := NewQuery().Table("user").Columns("id")
+	raw.JoinExpress(Raw("INNER JOIN orders ON orders.user_id = user.id"))
+	_, ok = raw.Tables()
+	assert.False(t, ok)
+}
@@ -14,6 +14,13 @@ func Raw(raw string, binds ...any) ksql.ExpressInterface {
 	return express.NewStatement(raw, binds)
 }
 
+// RawNamed expands :name / @name placeholders of raw to positional binds,
+// params is a map with string keys, an express.NamedInterface or a struct whose fields are named by db tag
+func RawNamed(raw string, params any) (ksql.ExpressInterface, error) {
+	raw = strings.Trim(raw, " \n\r\t\v")
+	return express.NewNamedStatement(raw, params)
+}
+
 func RawValue(val any) string {
 	switch tmp := val.(type) {
 	case string:
@@ -63,3 +63,28 @@ func TestRawFormatSharding(t *testing.T) {
 	assert.Equal(t, fmt.Sprintf("kovey_%s", time.Now().Format(ksql.Month_Format)), _formatSharding("kovey", ksql.Sharding_Month))
 	assert.Equal(t, "kovey", _formatSharding("kovey", ksql.Sharding_None))
 }
+
+func TestRawNamed(t *testing.T) {
+	raw, err := RawNamed("SELECT * FROM user WHERE status = :status AND id IN (:ids) AND name = @name", map[string]any{"status": 1, "ids": []int{1, 2}, "name": "kovey"})
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM user WHERE status = ? AND id IN (?, ?) AND name = ?", raw.Statement())
+	assert.Equal(t, []any{1, 1, 2, "kovey"}, raw.Binds())
+	assert.Equal(t, "SELECT * FROM user WHERE status = 1 AND id IN (1, 2) AND name = 'kovey'", DefaultEngine().FormatRaw(raw))
+
+	named, err := RawNamed(" UPDATE user SET age = :age WHERE id = :id ", struct {
+		Id  int `db:"id"`
+		Age int `db:"age"`
+	}{Id: 3, Age: 18})
+	assert.Nil(t, err)
+	assert.Equal(t, "UPDATE user SET age = ? WHERE id = ?", named.Statement())
+	assert.Equal(t, []any{18, 3}, named.Binds())
+	assert.True(t, named.IsExec())
+
+	_, err = RawNamed("SELECT * FROM user WHERE id = :id", map[string]any{})
+	assert.NotNil(t, err)
+
+	// a single map bind of Raw stays a positional bind
+	positional := Raw("SELECT * FROM user WHERE meta = ? AND id = :id", map[string]any{"id": 1})
+	assert.Equal(t, "SELECT * FROM user WHERE meta = ? AND id = :id", positional.Statement())
+	assert.Equal(t, []any{map[string]any{"id": 1}}, positional.Binds())
+}
@@ -0,0 +1,96 @@
+package sql
+
+import (
+	ksql "github.com/kovey/db-go/v3"
+)
+
+// Tables read by the query: its table, derived tables, joins, subqueries of where and having, CTEs and compound parts.
+// ok is false when a source can not be named, such as a join by raw express; raw conditions are not parsed
+func (o *Query) Tables() ([]string, bool) {
+	var tables []string
+	ok := o._tables(&tables)
+	return tables, ok
+}
+
+func (o *Query) _tables(tables *[]string) bool {
+	ok := true
+	sub := func(query ksql.QueryInterface) {
+		if q, isQuery := query.(*Query); !isQuery || !q._tables(tables) {
+			ok = false
+		}
+	}
+
+	if o.table.sub != nil {
+		sub(o.table.sub)
+	} else if o.table.table != "" {
+		*tables = append(*tables, o.table.table)
+	}
+
+	for _, join := range o.join {
+		j, isJoin := join.(*Join)
+		if !isJoin || j.isExpress {
+			ok = false
+			continue
+		}
+		*tables = append(*tables, j.table)
+	}
+
+	if where, isWhere := o.where.(*Where); isWhere {
+		for _, query := range where.subs() {
+			sub(query)
+		}
+	} else if o.where != nil {
+		ok = false
+	}
+
+	if having, isHaving := o.having.(*Having); isHaving {
+		for _, query := range having.subs() {
+			sub(query)
+		}
+	} else if o.having != nil {
+		ok = false
+	}
+
+	for _, c := range o.with.ctes {
+		sub(c.query)
+	}
+
+	for _, c := range o.compounds {
+		sub(c.query)
+	}
+
+	return ok
+}
+
+func opSubs(ops []*whereOp) []ksql.QueryInterface {
+	var subs []ksql.QueryInterface
+	for _, op := range ops {
+		if op.sub != nil {
+			subs = append(subs, op.sub)
+		}
+	}
+	return subs
+}
+
+// subqueries of the conditions and their groups
+func (w *Where) subs() []ksql.QueryInterface {
+	subs := opSubs(w.ops)
+	for _, group := range w.andWheres {
+		subs = append(subs, group.subs()...)
+	}
+	for _, group := range w.orWheres {
+		subs = append(subs, group.subs()...)
+	}
+	return subs
+}
+
+func (w *Having) subs() []ksql.QueryInterface {
+	subs := opSubs(w.ops)
+	for _, group := range w.andWheres {
+		subs = append(subs, group.subs()...)
+	}
+	for _, group := range w.orWheres {
+		subs = append(subs, group.subs()...)
+	}
+	return subs
+}
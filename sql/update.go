@@ -17,19 +17,33 @@ type Update struct {
 	ignore      string
 	order       *orderInfo
 	limit       string
+	with        *withInfo
+	hints       *optimizerHints
+	indexHints  *indexHints
 }
 
 func NewUpdate() *Update {
-	u := &Update{base: newBase(), assignments: &assignments{}, order: &orderInfo{}}
-	u.opChain.Append(u._keyword, u._set, u._where, u._order, u._limit)
+	u := &Update{base: newBase(), assignments: &assignments{}, order: &orderInfo{}, with: &withInfo{}, hints: &optimizerHints{}, indexHints: &indexHints{}}
+	u.opChain.Append(u._with, u._keyword, u._set, u._where, u._order, u._limit)
 	return u
 }
 
+func (u *Update) _with(builder *strings.Builder) {
+	if u.with.Empty() {
+		return
+	}
+
+	u.with.Build(builder)
+	u.binds = append(u.binds, u.with.Binds()...)
+}
+
 func (u *Update) _keyword(builder *strings.Builder) {
 	builder.WriteString("UPDATE")
+	u.hints.Build(builder)
 	operator.BuildPureString(u.priority, builder)
 	operator.BuildPureString(u.ignore, builder)
 	operator.BuildColumnString(u.table, builder)
+	u.indexHints.Build(builder)
 }
 
 func (u *Update) _set(builder *strings.Builder) {
@@ -127,3 +141,63 @@ func (u *Update) Limit(limit int) ksql.UpdateInterface {
 	u.limit = strconv.Itoa(limit)
 	return u
 }
+
+func (u *Update) With(name string, query ksql.QueryInterface, columns ...string) ksql.UpdateInterface {
+	u.with.Append(&cte{name: name, query: query, columns: columns}, false)
+	return u
+}
+
+func (u *Update) WithRecursive(name string, query ksql.QueryInterface, columns ...string) ksql.UpdateInterface {
+	u.with.Append(&cte{name: name, query: query, columns: columns}, true)
+	return u
+}
+
+func (u *Update) JsonSet(column, path string, value any) ksql.UpdateInterface {
+	return u.SetExpress(jsonSet(column, path, value))
+}
+
+func (u *Update) JsonRemove(column string, paths ...string) ksql.UpdateInterface {
+	return u.SetExpress(jsonRemove(column, paths...))
+}
+
+func (u *Update) UseIndex(indexes ...string) ksql.UpdateInterface {
+	u.indexHints.Append("USE", indexes)
+	return u
+}
+
+func (u *Update) ForceIndex(indexes ...string) ksql.UpdateInterface {
+	u.indexHints.Append("FORCE", indexes)
+	return u
+}
+
+func (u *Update) IgnoreIndex(indexes ...string) ksql.UpdateInterface {
+	u.indexHints.Append("IGNORE", indexes)
+	return u
+}
+
+func (u *Update) Hint(hints ...string) ksql.UpdateInterface {
+	for _, hint := range hints {
+		u.hints.Append(hint)
+	}
+	return u
+}
+
+func (u *Update) HintIndex(table string, indexes ...string) ksql.UpdateInterface {
+	u.hints.Append(tableHint("INDEX", table, indexes))
+	return u
+}
+
+func (u *Update) HintNoIndex(table string, indexes ...string) ksql.UpdateInterface {
+	u.hints.Append(tableHint("NO_INDEX", table, indexes))
+	return u
+}
+
+func (u *Update) HintJoinOrder(tables ...string) ksql.UpdateInterface {
+	u.hints.Append(joinOrderHint(tables))
+	return u
+}
+
+func (u *Update) HintSetVar(name string, value any) ksql.UpdateInterface {
+	u.hints.Append(setVarHint(name, value))
+	return u
+}
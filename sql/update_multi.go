@@ -15,14 +15,24 @@ type UpdateMulti struct {
 	joins       []ksql.JoinInterface
 	ignore      string
 	priority    string
+	with        *withInfo
 }
 
 func NewUpdateMulti() *UpdateMulti {
-	u := &UpdateMulti{base: newBase(), assignments: &assignments{}}
-	u.opChain.Append(u._keyword, u._table, u._set, u._where)
+	u := &UpdateMulti{base: newBase(), assignments: &assignments{}, with: &withInfo{}}
+	u.opChain.Append(u._with, u._keyword, u._table, u._set, u._where)
 	return u
 }
 
+func (u *UpdateMulti) _with(builder *strings.Builder) {
+	if u.with.Empty() {
+		return
+	}
+
+	u.with.Build(builder)
+	u.binds = append(u.binds, u.with.Binds()...)
+}
+
 func (u *UpdateMulti) _keyword(builder *strings.Builder) {
 	builder.WriteString("UPDATE")
 	operator.BuildPureString(u.priority, builder)
@@ -112,3 +122,13 @@ func (u *UpdateMulti) Ignore() ksql.UpdateMultiInterface {
 	u.ignore = "IGNORE"
 	return u
 }
+
+func (u *UpdateMulti) With(name string, query ksql.QueryInterface, columns ...string) ksql.UpdateMultiInterface {
+	u.with.Append(&cte{name: name, query: query, columns: columns}, false)
+	return u
+}
+
+func (u *UpdateMulti) WithRecursive(name string, query ksql.QueryInterface, columns ...string) ksql.UpdateMultiInterface {
+	u.with.Append(&cte{name: name, query: query, columns: columns}, true)
+	return u
+}
@@ -16,3 +16,30 @@ func TestUpdate(t *testing.T) {
 	assert.Equal(t, []any{"kovey", 10, 1}, u.Binds())
 	assert.Equal(t, "UPDATE `user` SET `name` = ?, `age` = ? WHERE `id` = ?", u.Prepare())
 }
+
+func TestUpdateWith(t *testing.T) {
+	active := NewQuery().Table("login").Columns("user_id").Where("day", ">", 7)
+	w := NewWhere()
+	w.Where("status", "=", 0)
+	w.InBy("id", NewQuery().Table("recent").Columns("user_id"))
+	u := NewUpdate().With("recent", active, "user_id").Table("user").Set("status", 1).Where(w)
+
+	assert.Equal(t, "WITH `recent` (`user_id`) AS (SELECT `user_id` FROM `login` WHERE `day` > ?) UPDATE `user` SET `status` = ? WHERE `status` = ? AND `id` IN (SELECT `user_id` FROM `recent`)", u.Prepare())
+	assert.Equal(t, []any{7, 1, 0}, u.Binds())
+}
+
+func TestUpdateJson(t *testing.T) {
+	w := NewWhere()
+	w.Where("id", "=", 1)
+	u := NewUpdate().Table("user").JsonSet("profile", "$.age", 20).JsonSet("profile", "$.tags", []string{"a"}).JsonRemove("profile", "$.old", "$.tmp").Where(w)
+
+	assert.Equal(t, "UPDATE `user` SET `profile` = JSON_SET(`profile`, ?, ?), `profile` = JSON_SET(`profile`, ?, CAST(? AS JSON)), `profile` = JSON_REMOVE(`profile`, ?, ?) WHERE `id` = ?", u.Prepare())
+	assert.Equal(t, []any{"$.age", 20, "$.tags", `["a"]`, "$.old", "$.tmp", 1}, u.Binds())
+}
+
+func TestUpdateHints(t *testing.T) {
+	u := NewUpdate()
+	u.Table("user").Set("status", 2).Where(NewWhere().Where("group_id", "=", 1)).ForceIndex("idx_group_id").HintSetVar("innodb_lock_wait_timeout", 3).Limit(10)
+	assert.Equal(t, "UPDATE /*+ SET_VAR(innodb_lock_wait_timeout = 3) */ `user` FORCE INDEX (`idx_group_id`) SET `status` = ? WHERE `group_id` = ? LIMIT 10", u.Prepare())
+	assert.Equal(t, []any{2, 1}, u.Binds())
+}
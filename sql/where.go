@@ -19,6 +19,9 @@ type whereOp struct {
 	expr       ksql.ExpressInterface
 	sub        ksql.QueryInterface
 	isBetween  bool
+	isColumn   bool
+	row        []string
+	rows       [][]any
 }
 
 func (w *whereOp) binds() []any {
@@ -30,10 +33,18 @@ func (w *whereOp) binds() []any {
 		return w.sub.Binds()
 	}
 
-	if w.isConst {
+	if w.isConst || w.isColumn {
 		return nil
 	}
 
+	if w.rows != nil {
+		var binds []any
+		for _, row := range w.rows {
+			binds = append(binds, row...)
+		}
+		return binds
+	}
+
 	if w.isArr || w.isBetween {
 		return w.values
 	}
@@ -47,12 +58,20 @@ func (w *whereOp) Build(builder *strings.Builder) {
 		return
 	}
 
-	operator.Column(w.column, builder)
+	if w.column == "" && len(w.row) == 0 {
+		builder.WriteString(string(w.op))
+		w.buildSub(builder)
+		return
+	}
+
+	if len(w.row) > 0 {
+		buildRow(w.row, builder)
+	} else {
+		operator.Column(w.column, builder)
+	}
 	operator.BuildPureString(string(w.op), builder)
 	if w.sub != nil {
-		builder.WriteString(" (")
-		builder.WriteString(w.sub.Prepare())
-		builder.WriteString(")")
+		w.buildSub(builder)
 		return
 	}
 
@@ -61,6 +80,24 @@ func (w *whereOp) Build(builder *strings.Builder) {
 		return
 	}
 
+	if w.isColumn {
+		builder.WriteString(" ")
+		operator.Column(w.constValue, builder)
+		return
+	}
+
+	if w.rows != nil {
+		builder.WriteString(" (")
+		for index, row := range w.rows {
+			if index > 0 {
+				builder.WriteString(", ")
+			}
+			buildPlaceholders(len(row), builder)
+		}
+		builder.WriteString(")")
+		return
+	}
+
 	if w.isBetween {
 		operator.BuildPureString("?", builder)
 		operator.BuildPureString("AND", builder)
@@ -73,16 +110,91 @@ func (w *whereOp) Build(builder *strings.Builder) {
 		return
 	}
 
+	builder.WriteString(" ")
+	buildPlaceholders(len(w.values), builder)
+}
+
+func (w *whereOp) buildSub(builder *strings.Builder) {
 	builder.WriteString(" (")
-	for index := range w.values {
+	builder.WriteString(w.sub.Prepare())
+	builder.WriteString(")")
+}
+
+func buildRow(columns []string, builder *strings.Builder) {
+	builder.WriteString("(")
+	for index, column := range columns {
 		if index > 0 {
 			builder.WriteString(", ")
 		}
+		operator.Column(column, builder)
+	}
+	builder.WriteString(")")
+}
+
+func buildPlaceholders(count int, builder *strings.Builder) {
+	builder.WriteString("(")
+	for i := 0; i < count; i++ {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
 		builder.WriteString("?")
 	}
 	builder.WriteString(")")
 }
 
+func existsOp(op string, sub ksql.QueryInterface) *whereOp {
+	return &whereOp{op: ksql.Op(op), sub: sub}
+}
+
+func subOp(column string, op ksql.Op, sub ksql.QueryInterface) *whereOp {
+	if !ksql.SupportOp(op) {
+		panic(fmt.Sprintf("op %s not support", op))
+	}
+
+	return &whereOp{column: column, op: op, sub: sub}
+}
+
+func columnOp(column string, op ksql.Op, other string) *whereOp {
+	if !ksql.SupportOp(op) {
+		panic(fmt.Sprintf("op %s not support", op))
+	}
+
+	return &whereOp{column: column, op: op, constValue: other, isColumn: true}
+}
+
+func rowOp(columns []string, op ksql.Op, values []any) *whereOp {
+	if !ksql.SupportOp(op) {
+		panic(fmt.Sprintf("op %s not support", op))
+	}
+	if len(columns) != len(values) {
+		panic(fmt.Sprintf("row has %d columns but %d values", len(columns), len(values)))
+	}
+
+	return &whereOp{row: columns, op: op, values: values, isArr: true}
+}
+
+func rowInOp(columns []string, op string, rows [][]any) *whereOp {
+	for _, row := range rows {
+		if len(row) != len(columns) {
+			panic(fmt.Sprintf("row has %d columns but %d values", len(columns), len(row)))
+		}
+	}
+
+	// an empty list matches no row for IN and every row for NOT IN, like an empty named slice renders IN (NULL)
+	if len(rows) == 0 {
+		if op == "IN" {
+			return &whereOp{expr: Raw("1 = 0")}
+		}
+		return &whereOp{expr: Raw("1 = 1")}
+	}
+
+	return &whereOp{row: columns, op: ksql.Op(op), rows: rows}
+}
+
+func rowInByOp(columns []string, op string, sub ksql.QueryInterface) *whereOp {
+	return &whereOp{row: columns, op: ksql.Op(op), sub: sub}
+}
+
 type Where struct {
 	ops       []*whereOp
 	orWheres  []*Where
@@ -98,14 +210,28 @@ func NewWhere() *Where {
 	return w
 }
 
+// Clone copies the groups too, a group is built only once
 func (w *Where) Clone() ksql.WhereInterface {
 	o := NewWhere()
-	o.ops = w.ops
-	o.orWheres = w.orWheres
-	o.andWheres = w.andWheres
+	o.ops = append([]*whereOp(nil), w.ops...)
+	o.onlyBody = w.onlyBody
+	o.orWheres = cloneWheres(w.orWheres)
+	o.andWheres = cloneWheres(w.andWheres)
 	return o
 }
 
+func cloneWheres(wheres []*Where) []*Where {
+	if wheres == nil {
+		return nil
+	}
+
+	clones := make([]*Where, len(wheres))
+	for index, where := range wheres {
+		clones[index] = where.Clone().(*Where)
+	}
+	return clones
+}
+
 func (w *Where) _keyword(builder *strings.Builder) {
 	if w.onlyBody {
 		return
@@ -154,8 +280,8 @@ func (w *Where) _where(prefix, op string, wheres []*Where, builder *strings.Buil
 		return
 	}
 
-	builder.WriteString(" ")
 	if prefix != "" {
+		builder.WriteString(" ")
 		builder.WriteString(prefix)
 	}
 
@@ -165,7 +291,11 @@ func (w *Where) _where(prefix, op string, wheres []*Where, builder *strings.Buil
 			builder.WriteString(op)
 		}
 
-		builder.WriteString(" (")
+		// a group body starts without space
+		if index > 0 || prefix != "" || !w.onlyBody {
+			builder.WriteString(" ")
+		}
+		builder.WriteString("(")
 		where.Build(builder)
 		builder.WriteString(")")
 		w.binds = append(w.binds, where.Binds()...)
@@ -266,3 +396,64 @@ func (w *Where) Empty() bool {
 func (w *Where) Binds() []any {
 	return w.binds
 }
+
+func (w *Where) Exists(sub ksql.QueryInterface) ksql.WhereInterface {
+	w.ops = append(w.ops, existsOp("EXISTS", sub))
+	return w
+}
+
+func (w *Where) NotExists(sub ksql.QueryInterface) ksql.WhereInterface {
+	w.ops = append(w.ops, existsOp("NOT EXISTS", sub))
+	return w
+}
+
+func (w *Where) WhereBy(column string, op ksql.Op, sub ksql.QueryInterface) ksql.WhereInterface {
+	w.ops = append(w.ops, subOp(column, op, sub))
+	return w
+}
+
+func (w *Where) Column(column string, op ksql.Op, other string) ksql.WhereInterface {
+	w.ops = append(w.ops, columnOp(column, op, other))
+	return w
+}
+
+func (w *Where) Row(columns []string, op ksql.Op, values []any) ksql.WhereInterface {
+	w.ops = append(w.ops, rowOp(columns, op, values))
+	return w
+}
+
+func (w *Where) RowIn(columns []string, rows [][]any) ksql.WhereInterface {
+	w.ops = append(w.ops, rowInOp(columns, "IN", rows))
+	return w
+}
+
+func (w *Where) RowNotIn(columns []string, rows [][]any) ksql.WhereInterface {
+	w.ops = append(w.ops, rowInOp(columns, "NOT IN", rows))
+	return w
+}
+
+func (w *Where) RowInBy(columns []string, sub ksql.QueryInterface) ksql.WhereInterface {
+	w.ops = append(w.ops, rowInByOp(columns, "IN", sub))
+	return w
+}
+
+func (w *Where) RowNotInBy(columns []string, sub ksql.QueryInterface) ksql.WhereInterface {
+	w.ops = append(w.ops, rowInByOp(columns, "NOT IN", sub))
+	return w
+}
+
+func (w *Where) JsonContains(column, path string, value any) ksql.WhereInterface {
+	return w.Express(jsonContains(column, path, value))
+}
+
+func (w *Where) JsonExtract(column, path string, op ksql.Op, value any) ksql.WhereInterface {
+	return w.Express(jsonExtract(column, path, op, value))
+}
+
+func (w *Where) MemberOf(column string, value any) ksql.WhereInterface {
+	return w.Express(memberOf(column, value))
+}
+
+func (w *Where) Match(columns []string, query string, mode ksql.MatchMode) ksql.WhereInterface {
+	return w.Express(Match(columns, query, mode))
+}
@@ -1,6 +1,7 @@
 package sql
 
 import (
+	"database/sql/driver"
 	"strings"
 	"testing"
 
@@ -29,3 +30,64 @@ func TestWhere(t *testing.T) {
 	assert.Equal(t, []any{10, 20, "kovey", 100, 1, 2, 3, 1, 1000, 2000, 123, 45, 1, 1, 100, 200, "%kk%"}, h.Binds())
 	assert.Equal(t, "WHERE `a`.`id` BETWEEN ? AND ? AND b.name = ? AND `a`.`age` > ? AND `a`.`sex` IN (?, ?, ?) AND `b`.`status` IN (SELECT `user_id` FROM `user` WHERE `status` <> ?) AND `a`.`mail` IS NOT NULL AND `b`.`avatar` IS NULL AND `a`.`num` NOT BETWEEN ? AND ? AND `c`.`id` NOT IN (?, ?) AND `c`.`test` NOT IN (SELECT `user_id` FROM `user` WHERE `status` <> ?) AND (`d`.`test` IS NOT NULL AND `d`.`ss` <> ?) OR (`d`.`info` BETWEEN ? AND ? AND `d`.`other` like ?)", builder.String())
 }
+
+func TestWhereSubquery(t *testing.T) {
+	orders := NewQuery().Table("orders").As("o").Columns("o.id").WhereColumn("o.user_id", ksql.Eq, "u.id").Where("o.status", ksql.Eq, 1)
+	avg := NewQuery().Table("user").Func("AVG", "balance", "")
+	pairs := NewQuery().Table("vip").Columns("game_id", "user_id").Where("level", ksql.Gt, 3)
+
+	w := NewWhere()
+	w.Exists(orders).NotExists(NewQuery().Table("ban").Columns("id").WhereColumn("ban.user_id", ksql.Eq, "u.id"))
+	w.WhereBy("u.balance", ksql.Gt, avg)
+	w.Row([]string{"u.game_id", "u.id"}, ksql.Gt, []any{3, 100})
+	w.RowIn([]string{"u.game_id", "u.room_id"}, [][]any{{1, 2}, {3, 4}}).RowInBy([]string{"u.game_id", "u.id"}, pairs)
+	w.RowNotIn([]string{"u.a", "u.b"}, [][]any{{5, 6}})
+
+	var builder strings.Builder
+	w.Build(&builder)
+	assert.Equal(t, "WHERE EXISTS (SELECT `o`.`id` FROM `orders` AS `o` WHERE `o`.`user_id` = `u`.`id` AND `o`.`status` = ?) AND NOT EXISTS (SELECT `id` FROM `ban` WHERE `ban`.`user_id` = `u`.`id`) AND `u`.`balance` > (SELECT AVG(`balance`) FROM `user`) AND (`u`.`game_id`, `u`.`id`) > (?, ?) AND (`u`.`game_id`, `u`.`room_id`) IN ((?, ?), (?, ?)) AND (`u`.`game_id`, `u`.`id`) IN (SELECT `game_id`, `user_id` FROM `vip` WHERE `level` > ?) AND (`u`.`a`, `u`.`b`) NOT IN ((?, ?))", builder.String())
+	assert.Equal(t, []any{1, 3, 100, 1, 2, 3, 4, 3, 5, 6}, w.Binds())
+
+	assert.Panics(t, func() { NewWhere().Row([]string{"a", "b"}, ksql.Eq, []any{1}) })
+	assert.Panics(t, func() { NewWhere().RowIn([]string{"a", "b"}, [][]any{{1, 2}, {3}}) })
+	assert.Panics(t, func() { NewWhere().WhereBy("a", "IN", avg) })
+
+	// an empty list is always false for IN and always true for NOT IN
+	empty := NewWhere().RowIn([]string{"a", "b"}, nil).RowNotIn([]string{"a", "b"}, [][]any{}).Where("c", ksql.Eq, 1)
+	builder.Reset()
+	empty.Build(&builder)
+	assert.Equal(t, "WHERE 1 = 0 AND 1 = 1 AND `c` = ?", builder.String())
+	assert.Equal(t, []any{1}, empty.Binds())
+}
+
+func TestWhereJson(t *testing.T) {
+	w := NewWhere()
+	w.JsonContains("tags", "", []string{"go"}).JsonContains("profile", "$.roles", "admin").JsonExtract("profile", "$.age", ksql.Ge, 18)
+	w.JsonExtract("profile", "$.address", ksql.Eq, map[string]string{"city": "sh"}).MemberOf("ids", 3)
+
+	var builder strings.Builder
+	w.Build(&builder)
+	assert.Equal(t, "WHERE JSON_CONTAINS(`tags`, ?) AND JSON_CONTAINS(`profile`, ?, ?) AND JSON_EXTRACT(`profile`, ?) >= ? AND JSON_EXTRACT(`profile`, ?) = CAST(? AS JSON) AND ? MEMBER OF(`ids`)", builder.String())
+	assert.Equal(t, []any{`["go"]`, `"admin"`, "$.roles", "$.age", 18, "$.address", `{"city":"sh"}`, 3}, w.Binds())
+	assert.Panics(t, func() { NewWhere().JsonExtract("a", "$.b", "IN", 1) })
+
+	// a value json can not encode is reported by the driver when the bind is converted
+	w = NewWhere()
+	w.JsonContains("tags", "", make(chan int))
+	builder.Reset()
+	w.Build(&builder)
+	binds := w.Binds()
+	assert.Equal(t, 1, len(binds))
+	_, err := binds[0].(driver.Valuer).Value()
+	assert.ErrorContains(t, err, "unsupported type")
+}
+
+func TestWhereMatch(t *testing.T) {
+	w := NewWhere()
+	w.Match([]string{"title", "body"}, "golang orm", ksql.Match_Natural_Language).Match([]string{"title"}, "+mysql -oracle", ksql.Match_Boolean).Match([]string{"body"}, "database", "")
+
+	var builder strings.Builder
+	w.Build(&builder)
+	assert.Equal(t, "WHERE MATCH (`title`, `body`) AGAINST (? IN NATURAL LANGUAGE MODE) AND MATCH (`title`) AGAINST (? IN BOOLEAN MODE) AND MATCH (`body`) AGAINST (?)", builder.String())
+	assert.Equal(t, []any{"golang orm", "+mysql -oracle", "database"}, w.Binds())
+}
@@ -0,0 +1,274 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+
+	ksql "github.com/kovey/db-go/v3"
+	"github.com/kovey/db-go/v3/sql/operator"
+)
+
+type frame struct {
+	unit  string
+	start ksql.FrameBound
+	end   ksql.FrameBound
+}
+
+func (f *frame) Build(builder *strings.Builder) {
+	builder.WriteString(f.unit)
+	if f.end == "" {
+		operator.BuildPureString(string(f.start), builder)
+		return
+	}
+
+	builder.WriteString(" BETWEEN ")
+	builder.WriteString(string(f.start))
+	builder.WriteString(" AND ")
+	builder.WriteString(string(f.end))
+}
+
+// window spec: [name] [PARTITION BY ...] [ORDER BY ...] [ROWS|RANGE frame]
+type Window struct {
+	*base
+	name       string
+	partitions *columnInfos
+	order      *orderInfo
+	frame      *frame
+}
+
+func NewWindow() *Window {
+	w := &Window{base: newBase(), partitions: &columnInfos{}, order: &orderInfo{}}
+	w.opChain.Append(w._name, w._partition, w._order, w._frame)
+	return w
+}
+
+func (w *Window) _space(builder *strings.Builder) {
+	if builder.Len() > 0 {
+		builder.WriteString(" ")
+	}
+}
+
+func (w *Window) _name(builder *strings.Builder) {
+	if w.name == "" {
+		return
+	}
+
+	operator.Column(w.name, builder)
+}
+
+func (w *Window) _partition(builder *strings.Builder) {
+	if w.partitions.Empty() {
+		return
+	}
+
+	w._space(builder)
+	builder.WriteString("PARTITION BY ")
+	w.partitions.Build(builder)
+}
+
+func (w *Window) _order(builder *strings.Builder) {
+	if w.order.Empty() {
+		return
+	}
+
+	if builder.Len() > 0 {
+		w.order.Build(builder)
+		return
+	}
+
+	var order strings.Builder
+	w.order.Build(&order)
+	builder.WriteString(strings.TrimPrefix(order.String(), " "))
+}
+
+func (w *Window) _frame(builder *strings.Builder) {
+	if w.frame == nil {
+		return
+	}
+
+	w._space(builder)
+	w.frame.Build(builder)
+}
+
+func (w *Window) Base(name string) ksql.WindowInterface {
+	w.name = name
+	return w
+}
+
+func (w *Window) PartitionBy(columns ...string) ksql.WindowInterface {
+	for _, column := range columns {
+		w.partitions.Append(&columnInfo{column: column})
+	}
+	return w
+}
+
+func (w *Window) Order(columns ...string) ksql.WindowInterface {
+	for _, column := range columns {
+		w.order.Append(&orderMeta{column: &columnInfo{column: column}, typ: "ASC"})
+	}
+	return w
+}
+
+func (w *Window) OrderDesc(columns ...string) ksql.WindowInterface {
+	for _, column := range columns {
+		w.order.Append(&orderMeta{column: &columnInfo{column: column}, typ: "DESC"})
+	}
+	return w
+}
+
+func (w *Window) Rows(start, end ksql.FrameBound) ksql.WindowInterface {
+	w.frame = &frame{unit: "ROWS", start: start, end: end}
+	return w
+}
+
+func (w *Window) Range(start, end ksql.FrameBound) ksql.WindowInterface {
+	w.frame = &frame{unit: "RANGE", start: start, end: end}
+	return w
+}
+
+func (w *Window) Statement() string {
+	return w.Prepare()
+}
+
+func (w *Window) IsExec() bool {
+	return false
+}
+
+func (w *Window) Type() ksql.SqlType {
+	return ksql.Sql_Type_Query
+}
+
+// n PRECEDING
+func Preceding(n uint) ksql.FrameBound {
+	return ksql.FrameBound(fmt.Sprintf("%d PRECEDING", n))
+}
+
+// n FOLLOWING
+func Following(n uint) ksql.FrameBound {
+	return ksql.FrameBound(fmt.Sprintf("%d FOLLOWING", n))
+}
+
+// window function: FUNC(args) OVER (spec) | OVER `name`
+type WindowFunc struct {
+	*base
+	fun     string
+	column  string
+	args    []any
+	over    ksql.WindowInterface
+	overRef string
+	as      string
+}
+
+func NewWindowFunc(fun string, column string, args ...any) *WindowFunc {
+	f := &WindowFunc{base: newBase(), fun: fun, column: column, args: args}
+	f.opChain.Append(f._func, f._over, f._as)
+	return f
+}
+
+func (f *WindowFunc) _func(builder *strings.Builder) {
+	builder.WriteString(f.fun)
+	builder.WriteString("(")
+	if f.column != "" {
+		operator.Column(f.column, builder)
+	}
+	for index, arg := range f.args {
+		if index > 0 || f.column != "" {
+			builder.WriteString(", ")
+		}
+		if expr, ok := arg.(ksql.ExpressInterface); ok {
+			builder.WriteString(expr.Statement())
+			f.binds = append(f.binds, expr.Binds()...)
+			continue
+		}
+
+		builder.WriteString("?")
+		f.binds = append(f.binds, arg)
+	}
+	builder.WriteString(")")
+}
+
+func (f *WindowFunc) _over(builder *strings.Builder) {
+	builder.WriteString(" OVER")
+	if f.overRef != "" {
+		operator.BuildColumnString(f.overRef, builder)
+		return
+	}
+
+	builder.WriteString(" (")
+	if f.over != nil {
+		builder.WriteString(f.over.Statement())
+		f.binds = append(f.binds, f.over.Binds()...)
+	}
+	builder.WriteString(")")
+}
+
+func (f *WindowFunc) _as(builder *strings.Builder) {
+	if f.as == "" {
+		return
+	}
+
+	builder.WriteString(" AS")
+	operator.BuildColumnString(f.as, builder)
+}
+
+func (f *WindowFunc) Over(window ksql.WindowInterface) ksql.WindowFuncInterface {
+	f.over = window
+	return f
+}
+
+func (f *WindowFunc) OverName(name string) ksql.WindowFuncInterface {
+	f.overRef = name
+	return f
+}
+
+func (f *WindowFunc) As(as string) ksql.WindowFuncInterface {
+	f.as = as
+	return f
+}
+
+func (f *WindowFunc) Statement() string {
+	return f.Prepare()
+}
+
+func (f *WindowFunc) IsExec() bool {
+	return false
+}
+
+func (f *WindowFunc) Type() ksql.SqlType {
+	return ksql.Sql_Type_Query
+}
+
+func RowNumber() *WindowFunc {
+	return NewWindowFunc("ROW_NUMBER", "")
+}
+
+func Rank() *WindowFunc {
+	return NewWindowFunc("RANK", "")
+}
+
+func DenseRank() *WindowFunc {
+	return NewWindowFunc("DENSE_RANK", "")
+}
+
+// LAG(column, offset[, def])
+func Lag(column string, offset uint, def ...any) *WindowFunc {
+	return NewWindowFunc("LAG", column, _lagArgs(offset, def)...)
+}
+
+// LEAD(column, offset[, def])
+func Lead(column string, offset uint, def ...any) *WindowFunc {
+	return NewWindowFunc("LEAD", column, _lagArgs(offset, def)...)
+}
+
+func _lagArgs(offset uint, def []any) []any {
+	args := []any{Raw(fmt.Sprintf("%d", offset))}
+	if len(def) > 0 {
+		args = append(args, def[0])
+	}
+
+	return args
+}
+
+func SumOver(column string) *WindowFunc {
+	return NewWindowFunc("SUM", column)
+}
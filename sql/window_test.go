@@ -0,0 +1,45 @@
+package sql
+
+import (
+	"testing"
+
+	ksql "github.com/kovey/db-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindowSpec(t *testing.T) {
+	assert.Equal(t, "PARTITION BY `dept` ORDER BY `salary` DESC", NewWindow().PartitionBy("dept").OrderDesc("salary").Statement())
+	assert.Equal(t, "ORDER BY `day` ASC ROWS BETWEEN 6 PRECEDING AND CURRENT ROW", NewWindow().Order("day").Rows(Preceding(6), ksql.Frame_Current_Row).Statement())
+	assert.Equal(t, "`w` RANGE UNBOUNDED PRECEDING", NewWindow().Base("w").Range(ksql.Frame_Unbounded_Preceding, "").Statement())
+	assert.Equal(t, "PARTITION BY `a`, `u`.`b` ROWS BETWEEN CURRENT ROW AND 2 FOLLOWING", NewWindow().PartitionBy("a", "u.b").Rows(ksql.Frame_Current_Row, Following(2)).Statement())
+}
+
+func TestWindowFunc(t *testing.T) {
+	f := RowNumber().Over(NewWindow().PartitionBy("dept").OrderDesc("salary")).As("rn")
+	assert.Equal(t, "ROW_NUMBER() OVER (PARTITION BY `dept` ORDER BY `salary` DESC) AS `rn`", f.Statement())
+	assert.Nil(t, f.Binds())
+
+	assert.Equal(t, "RANK() OVER `w`", Rank().OverName("w").Statement())
+	assert.Equal(t, "DENSE_RANK() OVER ()", DenseRank().Statement())
+
+	lag := Lag("amount", 1, 0).Over(NewWindow().Order("day")).As("prev")
+	assert.Equal(t, "LAG(`amount`, 1, ?) OVER (ORDER BY `day` ASC) AS `prev`", lag.Statement())
+	assert.Equal(t, []any{0}, lag.Binds())
+	assert.Equal(t, "LEAD(`amount`, 2) OVER `w`", Lead("amount", 2).OverName("w").Statement())
+	assert.Equal(t, "SUM(`amount`) OVER (ORDER BY `day` ASC ROWS UNBOUNDED PRECEDING) AS `total`", SumOver("amount").Over(NewWindow().Order("day").Rows(ksql.Frame_Unbounded_Preceding, "")).As("total").Statement())
+
+	// functions without a column start with their first arg
+	ntile := NewWindowFunc("NTILE", "", 4).Over(NewWindow().Order("score"))
+	assert.Equal(t, "NTILE(?) OVER (ORDER BY `score` ASC)", ntile.Statement())
+	assert.Equal(t, []any{4}, ntile.Binds())
+	assert.Equal(t, "NTH_VALUE(`amount`, ?) OVER `w`", NewWindowFunc("NTH_VALUE", "", Raw("`amount`"), 2).OverName("w").Statement())
+}
+
+func TestQueryWindow(t *testing.T) {
+	q := NewQuery().Table("salary").Columns("id", "dept").ColumnsExpress(
+		RowNumber().OverName("w").As("rn"),
+		Lag("amount", 1, 0).OverName("w").As("prev"),
+	).Where("year", "=", 2024).WindowBy("w", NewWindow().PartitionBy("dept").OrderDesc("amount"))
+	assert.Equal(t, "SELECT `id`, `dept`, ROW_NUMBER() OVER `w` AS `rn`, LAG(`amount`, 1, ?) OVER `w` AS `prev` FROM `salary` WHERE `year` = ? WINDOW `w` AS (PARTITION BY `dept` ORDER BY `amount` DESC)", q.Prepare())
+	assert.Equal(t, []any{0, 2024}, q.Binds())
+}
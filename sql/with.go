@@ -0,0 +1,70 @@
+package sql
+
+import (
+	"strings"
+
+	ksql "github.com/kovey/db-go/v3"
+	"github.com/kovey/db-go/v3/sql/operator"
+)
+
+type cte struct {
+	name    string
+	columns []string
+	query   ksql.QueryInterface
+}
+
+func (c *cte) Build(builder *strings.Builder) {
+	operator.BuildColumnString(c.name, builder)
+	if len(c.columns) > 0 {
+		builder.WriteString(" (")
+		for index, column := range c.columns {
+			if index > 0 {
+				builder.WriteString(", ")
+			}
+			operator.Column(column, builder)
+		}
+		builder.WriteString(")")
+	}
+
+	builder.WriteString(" AS (")
+	builder.WriteString(c.query.Prepare())
+	builder.WriteString(")")
+}
+
+type withInfo struct {
+	recursive bool
+	ctes      []*cte
+}
+
+func (w *withInfo) Empty() bool {
+	return len(w.ctes) == 0
+}
+
+func (w *withInfo) Append(c *cte, recursive bool) {
+	w.ctes = append(w.ctes, c)
+	w.recursive = w.recursive || recursive
+}
+
+func (w *withInfo) Build(builder *strings.Builder) {
+	builder.WriteString("WITH")
+	if w.recursive {
+		builder.WriteString(" RECURSIVE")
+	}
+
+	for index, c := range w.ctes {
+		if index > 0 {
+			builder.WriteString(",")
+		}
+		c.Build(builder)
+	}
+	builder.WriteString(" ")
+}
+
+func (w *withInfo) Binds() []any {
+	var binds []any
+	for _, c := range w.ctes {
+		binds = append(binds, c.query.Binds()...)
+	}
+
+	return binds
+}